@@ -0,0 +1,23 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build darwin linux
+
+// Flappy Gopher is a game in which the gopher runs, jumps, and flaps
+// over the ground and everything in its way. The game itself is in
+// package flappy; this is the app that runs it, with its assets.
+package main
+
+import (
+	"math/rand"
+	"time"
+
+	"github.com/adg/game/flappy"
+	"golang.org/x/mobile/app"
+)
+
+func main() {
+	rand.Seed(time.Now().UnixNano())
+	app.Main(flappy.Main)
+}
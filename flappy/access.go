@@ -0,0 +1,65 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build darwin linux
+
+package flappy
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/adg/game/sim"
+)
+
+const (
+	edgeWidth = 3   // width of each line drawn along the top of the ground in high contrast
+	hatchW    = 8   // width of the stripes painted over hazards for the colorblind
+	hatchDark = 0.5 // how much the dark stripes over hazards are darkened
+)
+
+// hazards are the textures striped for the colorblind.
+var hazards = []int{sim.TexPipe, sim.TexSpikes, sim.TexLava}
+
+// mutedSky is the tint of the sky in high contrast,
+// pulled towards grey so that the ground and everything on it stands out.
+func mutedSky(r, g, b float32) (float32, float32, float32) {
+	l := (r + g + b) / 3
+	return (r + l*3) / 4, (g + l*3) / 4, (b + l*3) / 4
+}
+
+// edgeGround draws a white line over a black one along the top
+// of the ground in r, so that its edge shows up on any sky.
+func edgeGround(m *image.RGBA, r image.Rectangle) {
+	for x := r.Min.X; x < r.Max.X; x++ {
+		y := r.Min.Y
+		for y < r.Max.Y && m.RGBAAt(x, y).A < 0x80 {
+			y++
+		}
+		for i := 0; i < edgeWidth*2 && y+i < r.Max.Y; i++ {
+			c := color.RGBA{0xff, 0xff, 0xff, 0xff}
+			if i >= edgeWidth {
+				c = color.RGBA{0x00, 0x00, 0x00, 0xff}
+			}
+			m.SetRGBA(x, y+i, c)
+		}
+	}
+}
+
+// hatch darkens diagonal stripes of the texture in r,
+// so that it can be told apart by its pattern as well as its colour.
+func hatch(m *image.RGBA, r image.Rectangle) {
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			if (x+y)/hatchW%2 == 0 {
+				continue
+			}
+			c := m.RGBAAt(x, y)
+			c.R = uint8(float32(c.R) * hatchDark)
+			c.G = uint8(float32(c.G) * hatchDark)
+			c.B = uint8(float32(c.B) * hatchDark)
+			m.SetRGBA(x, y, c)
+		}
+	}
+}
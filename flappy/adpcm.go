@@ -0,0 +1,114 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build darwin linux
+
+package flappy
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// WAV assets may hold IMA ADPCM samples, a quarter the size of 16-bit PCM,
+// so that the music doesn't balloon the app: the music tracks are encoded
+// that way, and the short sound effects are left as PCM. They are decoded
+// a block at a time as they are streamed.
+
+const wavADPCM = 0x11 // WAV format of IMA ADPCM samples
+
+// imaSteps are the step sizes of IMA ADPCM, by step index.
+var imaSteps = [89]int32{
+	7, 8, 9, 10, 11, 12, 13, 14, 16, 17, 19, 21, 23, 25, 28, 31, 34, 37, 41, 45,
+	50, 55, 60, 66, 73, 80, 88, 97, 107, 118, 130, 143, 157, 173, 190, 209, 230,
+	253, 279, 307, 337, 371, 408, 449, 494, 544, 598, 658, 724, 796, 876, 963,
+	1060, 1166, 1282, 1411, 1552, 1707, 1878, 2066, 2272, 2499, 2749, 3024, 3327,
+	3660, 4026, 4428, 4871, 5358, 5894, 6484, 7132, 7845, 8630, 9493, 10442,
+	11487, 12635, 13899, 15289, 16818, 18500, 20350, 22385, 24623, 27086, 29794,
+	32767,
+}
+
+// imaIndex is how each nibble moves the step index.
+var imaIndex = [16]int32{-1, -1, -1, -1, 2, 4, 6, 8, -1, -1, -1, -1, 2, 4, 6, 8}
+
+// An imaChannel is the decoder state of one channel of IMA ADPCM samples.
+type imaChannel struct {
+	sample int32 // the last sample decoded
+	index  int32 // index into imaSteps
+}
+
+// decode returns the sample that the nibble n encodes.
+func (c *imaChannel) decode(n byte) int16 {
+	step := imaSteps[c.index]
+	diff := step >> 3
+	if n&1 != 0 {
+		diff += step >> 2
+	}
+	if n&2 != 0 {
+		diff += step >> 1
+	}
+	if n&4 != 0 {
+		diff += step
+	}
+	if n&8 != 0 {
+		diff = -diff
+	}
+	c.sample = clampInt32(c.sample+diff, -32768, 32767)
+	c.index = clampInt32(c.index+imaIndex[n], 0, int32(len(imaSteps)-1))
+	return int16(c.sample)
+}
+
+func clampInt32(x, min, max int32) int32 {
+	if x < min {
+		return min
+	}
+	if x > max {
+		return max
+	}
+	return x
+}
+
+// decodeADPCM decodes the block b of IMA ADPCM samples, of the given
+// number of channels, appending them to pcm as 16-bit PCM.
+// Each channel's part of the block starts with its first sample and
+// step index, and the rest of its samples follow, 8 to every 4 bytes,
+// the channels taking turns.
+func decodeADPCM(pcm, b []byte, channels int) ([]byte, error) {
+	if len(b) < 4*channels {
+		return nil, errors.New("short ADPCM block")
+	}
+	group := 4 * channels // bytes of each turn of the channels
+	frames := 1 + (len(b)-group)/group*8
+	start := len(pcm)
+	for i := 0; i < frames*channels; i++ {
+		pcm = append(pcm, 0, 0)
+	}
+	out := pcm[start:]
+	put := func(frame, ch int, x int16) {
+		binary.LittleEndian.PutUint16(out[2*(frame*channels+ch):], uint16(x))
+	}
+
+	var chans [2]imaChannel
+	for ch := 0; ch < channels; ch++ {
+		h := b[4*ch:]
+		c := &chans[ch]
+		c.sample = int32(int16(binary.LittleEndian.Uint16(h)))
+		c.index = int32(h[2])
+		if c.index >= int32(len(imaSteps)) {
+			return nil, errors.New("bad ADPCM step index")
+		}
+		put(0, ch, int16(c.sample))
+	}
+	b = b[group:]
+	for frame := 1; len(b) >= group; frame += 8 {
+		for ch := 0; ch < channels; ch++ {
+			for i, v := range b[4*ch : 4*ch+4] {
+				put(frame+2*i, ch, chans[ch].decode(v&0xf))
+				put(frame+2*i+1, ch, chans[ch].decode(v>>4))
+			}
+		}
+		b = b[group:]
+	}
+	return pcm, nil
+}
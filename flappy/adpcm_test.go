@@ -0,0 +1,131 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build darwin linux
+
+package flappy
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"io/ioutil"
+	"reflect"
+	"testing"
+
+	"golang.org/x/mobile/exp/audio/al"
+)
+
+// The blocks below encode a few samples of sine waves, and their samples
+// are as decoded by Python's audioop, whose nibbles go the other way round.
+var adpcmTests = []struct {
+	channels int
+	block    string
+	want     []int16
+}{
+	{
+		channels: 1,
+		block:    "00000000777777f8ab8a3145",
+		want: []int16{
+			0, 11, 41, 104, 240, 533, 1164, 1074, -159,
+			-1392, -2193, -2921, -3053, -2693, -1927, -833, 478,
+		},
+	},
+	{
+		channels: 2,
+		block:    "00000000000000007777a7ffffffffff",
+		want: []int16{
+			0, 0, 11, -11, 41, -41, 104, -104, 240, -240,
+			533, -533, 323, -1164, -251, -2521, -1484, -5431,
+		},
+	},
+}
+
+func TestDecodeADPCM(t *testing.T) {
+	for _, tt := range adpcmTests {
+		b, err := hex.DecodeString(tt.block)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pcm, err := decodeADPCM(nil, b, tt.channels)
+		if err != nil {
+			t.Errorf("%d channels: %v", tt.channels, err)
+			continue
+		}
+		if got := samples(pcm); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%d channels: got %v, want %v", tt.channels, got, tt.want)
+		}
+	}
+}
+
+func TestDecodeADPCMErrors(t *testing.T) {
+	if _, err := decodeADPCM(nil, []byte{0, 0, 0}, 1); err == nil {
+		t.Error("short block decoded")
+	}
+	if _, err := decodeADPCM(nil, []byte{0, 0, 89, 0}, 1); err == nil {
+		t.Error("block with a bad step index decoded")
+	}
+}
+
+// TestWAVStreamADPCM streams a WAV file of two blocks, the second short,
+// then rewinds it and streams it again.
+func TestWAVStreamADPCM(t *testing.T) {
+	tt := adpcmTests[0]
+	block, _ := hex.DecodeString(tt.block)
+	short := block[:8]
+	data := append(append([]byte(nil), block...), short...)
+	want := append(append([]int16(nil), tt.want...), tt.want[:9]...)
+
+	var w bytes.Buffer
+	put := func(v ...interface{}) {
+		for _, v := range v {
+			binary.Write(&w, binary.LittleEndian, v)
+		}
+	}
+	w.WriteString("RIFF")
+	put(uint32(4 + 28 + 12 + 8 + len(data)))
+	w.WriteString("WAVEfmt ")
+	put(uint32(20), uint16(wavADPCM), uint16(1), uint32(22050), uint32(22050*12/17),
+		uint16(len(block)), uint16(4), uint16(2), uint16(17))
+	w.WriteString("fact")
+	put(uint32(4), uint32(len(want)))
+	w.WriteString("data")
+	put(uint32(len(data)))
+	w.Write(data)
+
+	s, err := readWAVHeader(nopCloser{bytes.NewReader(w.Bytes())})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f, rate := s.format(); f != al.FormatMono16 || rate != 22050 {
+		t.Errorf("format %d at %d Hz, want %d at 22050 Hz", f, rate, al.FormatMono16)
+	}
+	for i := 0; i < 2; i++ {
+		pcm, err := ioutil.ReadAll(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := samples(pcm); !reflect.DeepEqual(got, want) {
+			t.Errorf("pass %d: got %v, want %v", i, got, want)
+		}
+		if err := s.rewind(); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// samples returns the 16-bit samples of pcm.
+func samples(pcm []byte) []int16 {
+	s := make([]int16, len(pcm)/2)
+	for i := range s {
+		s[i] = int16(binary.LittleEndian.Uint16(pcm[2*i:]))
+	}
+	return s
+}
+
+type nopCloser struct {
+	*bytes.Reader
+}
+
+func (nopCloser) Close() error { return nil }
@@ -0,0 +1,72 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build darwin linux
+
+package flappy
+
+import (
+	"github.com/adg/game/sim"
+	"golang.org/x/mobile/exp/sprite/clock"
+)
+
+// An animFrame is a single frame of an animation.
+type animFrame struct {
+	tex int        // texture
+	d   clock.Time // how long the frame is shown
+}
+
+// An animation is a sequence of frames.
+type animation struct {
+	frames []animFrame
+	loop   bool // start again after the last frame, rather than hold it
+}
+
+// The gopher's animations.
+var (
+	animRun    = &animation{[]animFrame{{sim.TexGopherRun1, 4}, {sim.TexGopherRun2, 4}}, true}
+	animFall   = &animation{[]animFrame{{sim.TexGopherRun1, 8}, {sim.TexGopherRun2, 8}}, true}
+	animFlap   = &animation{[]animFrame{{sim.TexGopherFlap1, 3}, {sim.TexGopherFlap2, 5}}, true}
+	animClimb  = &animation{[]animFrame{{sim.TexGopherFlap1, 2}, {sim.TexGopherFlap2, 2}}, true}
+	animHang   = &animation{[]animFrame{{sim.TexGopherFlap1, 1}}, false}
+	animSlide  = &animation{[]animFrame{{sim.TexGopherSlide, 1}}, false}
+	animGlide  = &animation{[]animFrame{{sim.TexGopherGlide, 1}}, false}
+	animSwim   = &animation{[]animFrame{{sim.TexGopherSwim, 1}}, false}
+	animSquash = &animation{[]animFrame{{sim.TexGopherDead2, 1}}, false}
+	animDeath  = &animation{[]animFrame{{sim.TexGopherDead1, 6}, {sim.TexGopherDead2, 6}, {sim.TexGopherDead1, 12}, {sim.TexGopherDead2, 16}, {sim.TexGopherDead1, 16}}, true}
+	animFinish = &animation{[]animFrame{{sim.TexGopherRun1, 8}, {sim.TexGopherRun2, 8}, {sim.TexGopherFlap1, 8}, {sim.TexGopherFlap2, 8}}, true}
+)
+
+// tex returns the texture to show time t after the animation started.
+func (a *animation) tex(t clock.Time) int {
+	var total clock.Time
+	for _, f := range a.frames {
+		total += f.d
+	}
+	if a.loop {
+		t %= total
+	}
+	for _, f := range a.frames {
+		if t < f.d {
+			return f.tex
+		}
+		t -= f.d
+	}
+	return a.frames[len(a.frames)-1].tex
+}
+
+// An animator plays animations, starting each from its first frame
+// when it is switched to.
+type animator struct {
+	anim  *animation
+	start clock.Time
+}
+
+// tex plays animation a and returns the texture to show at time t.
+func (p *animator) tex(a *animation, t clock.Time) int {
+	if p.anim != a || t < p.start {
+		p.anim, p.start = a, t
+	}
+	return a.tex(t - p.start)
+}
@@ -0,0 +1,382 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build darwin linux
+
+package flappy
+
+import (
+	"log"
+	"time"
+
+	"github.com/adg/game/sim"
+	"golang.org/x/mobile/app"
+	"golang.org/x/mobile/event/key"
+	"golang.org/x/mobile/event/lifecycle"
+	"golang.org/x/mobile/event/mouse"
+	"golang.org/x/mobile/event/paint"
+	"golang.org/x/mobile/event/size"
+	"golang.org/x/mobile/event/touch"
+	"golang.org/x/mobile/exp/gl/glutil"
+	"golang.org/x/mobile/exp/sensor"
+	"golang.org/x/mobile/exp/sprite"
+	"golang.org/x/mobile/exp/sprite/clock"
+	"golang.org/x/mobile/exp/sprite/glsprite"
+	"golang.org/x/mobile/gl"
+)
+
+// Main plays the game in the app a until the app quits. Apps that are only
+// the game pass it to app.Main; others may run it on an app of their own.
+func Main(a app.App) {
+	var glctx gl.Context
+	var sz size.Event
+	go watchGamepads(a.Send)
+	go watchHeadphones(a.Send)
+	sensor.Notify(a)
+	for e := range a.Events() {
+		e = a.Filter(e)
+		if pressed(e) {
+			audio.unmute()
+		}
+		switch e := e.(type) {
+		case lifecycle.Event:
+			// The audio device stays open while the app is alive,
+			// and is paused while it is hidden.
+			if e.Crosses(lifecycle.StageAlive) == lifecycle.CrossOn {
+				audio.start()
+			}
+			switch e.Crosses(lifecycle.StageVisible) {
+			case lifecycle.CrossOn:
+				// App visible.
+				glctx, _ = e.DrawContext.(gl.Context)
+				onStart(glctx)
+				a.Send(paint.Event{})
+			case lifecycle.CrossOff:
+				// App no longer visible.
+				onStop()
+				glctx = nil
+			}
+			if e.Crosses(lifecycle.StageAlive) == lifecycle.CrossOff {
+				audio.stop()
+			}
+		case size.Event:
+			sz = e
+		case paint.Event:
+			if glctx == nil || e.External {
+				continue
+			}
+			onPaint(glctx, sz)
+			a.Publish()
+			a.Send(paint.Event{}) // keep animating
+		case touch.Event:
+			if game != nil {
+				onTouch(e, sz)
+			}
+		case sensor.Event:
+			if game != nil {
+				onSensor(e)
+			}
+		case headphonesEvent:
+			if !e.plugged {
+				// Don't blare out of the speaker: go quiet,
+				// and pause the run, until the player carries on.
+				audio.unplug()
+				if game != nil && screen == screenGame {
+					game.Pause()
+				}
+			}
+		case gamepadEvent:
+			if game != nil {
+				doActions(buttonActions(e.button), e.down)
+			}
+		case mouse.Event:
+			if game != nil {
+				onMouse(e)
+			}
+		case key.Event:
+			down := e.Direction == key.DirPress
+			if game == nil || !down && e.Direction != key.DirRelease {
+				break
+			}
+			if screen == screenMenu && menu.Rebinding() {
+				if down {
+					menu.Rebind(e.Code)
+				}
+				break
+			}
+			doActions(keyActions(e.Code), down)
+		}
+	}
+}
+
+// pressed reports whether e is the player pressing a key, button, or the screen.
+func pressed(e interface{}) bool {
+	switch e := e.(type) {
+	case touch.Event:
+		return e.Type == touch.TypeBegin
+	case key.Event:
+		return e.Direction == key.DirPress
+	case mouse.Event:
+		return e.Direction == mouse.DirPress
+	case gamepadEvent:
+		return e.down
+	}
+	return false
+}
+
+// onTouch handles a touch on a screen of size sz.
+func onTouch(e touch.Event, sz size.Event) {
+	switch e.Type {
+	case touch.TypeBegin:
+		fingers[e.Sequence] = true
+		if len(fingers) == 3 {
+			// A third finger takes a screenshot.
+			TakeScreenshot()
+			break
+		}
+		if screen == screenMenu {
+			titleTouch(e, sz)
+			break
+		}
+		if menuTouch(e, sz) {
+			break
+		}
+		if game.Settings().Controls == sim.ControlsSwipe {
+			gestures.begin(e, time.Now())
+			break
+		}
+		pressAction(e.Sequence, touchAction(e, sz))
+	case touch.TypeMove:
+		bindGestures(gestures.move(e, sz))
+	case touch.TypeEnd:
+		delete(fingers, e.Sequence)
+		bindGestures(gestures.end(e))
+		releaseAction(e.Sequence)
+	}
+}
+
+// onMouse handles the mouse on desktops that report it apart from touches.
+// The left button jumps and chooses, the middle one pauses,
+// and the wheel zooms the debug camera.
+func onMouse(e mouse.Event) {
+	switch e.Button {
+	case mouse.ButtonLeft, mouse.ButtonMiddle:
+		down := e.Direction == mouse.DirPress
+		if !down && e.Direction != mouse.DirRelease {
+			return
+		}
+		a := sim.ActionJump
+		if e.Button == mouse.ButtonMiddle {
+			a = sim.ActionPause
+		}
+		doActions([]int{a}, down)
+	case mouse.ButtonWheelUp:
+		if e.Direction != mouse.DirRelease {
+			game.ZoomDebug(+1)
+		}
+	case mouse.ButtonWheelDown:
+		if e.Direction != mouse.DirRelease {
+			game.ZoomDebug(-1)
+		}
+	}
+}
+
+// titleTouch handles a touch on the title screen.
+// Touches near the sides of the screen show the other items or settings,
+// and other touches choose the item shown.
+func titleTouch(e touch.Event, sz size.Event) {
+	w := float32(sz.WidthPx)
+	switch {
+	case e.X < w/4:
+		menu.Cycle(-1)
+	case e.X > w*3/4:
+		menu.Cycle(+1)
+	default:
+		menu.Press()
+	}
+}
+
+// menuTouch handles a touch on the character select screen, in the shop,
+// on the pause menu, or on the game over summary, and reports whether it
+// was used. Touches near the sides of the screen show the other characters,
+// items, or options, touches near the top change the mode, and touches
+// near the bottom go in and out of the shop. During a run, a touch in the
+// top-left corner pauses.
+func menuTouch(e touch.Event, sz size.Event) bool {
+	w, h := float32(sz.WidthPx), float32(sz.HeightPx)
+	switch {
+	case (game.Paused() || game.GameOver()) && e.X < w/4:
+		game.Cycle(-1)
+	case (game.Paused() || game.GameOver()) && e.X > w*3/4:
+		game.Cycle(+1)
+	case game.Paused() || game.GameOver():
+		return false
+	case e.X < w/8 && e.Y < h/8:
+		game.Pause()
+	case !game.Choosing():
+		return false
+	case e.X < w/4:
+		game.Cycle(-1)
+	case e.X > w*3/4:
+		game.Cycle(+1)
+	case e.Y < h/4:
+		game.CycleMode()
+	case e.Y > h*3/4 && game.Shopping():
+		game.CloseShop()
+	case e.Y > h*3/4:
+		game.OpenShop()
+	default:
+		return false
+	}
+	return true
+}
+
+// pressAction starts the touch seq holding down action a.
+// Each finger on the screen does its own thing until it is let go,
+// so a second finger may flap while the first is held to glide.
+func pressAction(seq touch.Sequence, a int) {
+	touches[seq] = a
+	held[a]++
+	gameAction(a, true)
+}
+
+// releaseAction lets go of the action held down by the touch seq,
+// once no other touch is holding it down.
+func releaseAction(seq touch.Sequence) {
+	a, ok := touches[seq]
+	if !ok {
+		return
+	}
+	delete(touches, seq)
+	if held[a]--; held[a] == 0 {
+		gameAction(a, false)
+	}
+}
+
+// touchAction returns the action of the touch e during a run, by where it is.
+func touchAction(e touch.Event, sz size.Event) int {
+	w, h := float32(sz.WidthPx), float32(sz.HeightPx)
+	switch game.Settings().Controls {
+	case sim.ControlsZones:
+		// Touches near the bottom of the screen slide,
+		// except in the corner, where they throw acorns.
+		switch {
+		case e.Y > h*3/4 && e.X > w*3/4:
+			return sim.ActionThrow
+		case e.Y > h*3/4:
+			return sim.ActionSlide
+		}
+	case sim.ControlsSplit:
+		// The left of the screen jumps, and the right throws.
+		if e.X > w/2 {
+			return sim.ActionThrow
+		}
+	}
+	return sim.ActionJump
+}
+
+// tick is how long each tick of the game's clock lasts.
+const tick = time.Second / sim.ClockRate
+
+// The screens the app can show.
+const (
+	screenMenu = iota // the title screen
+	screenGame        // the game itself
+)
+
+var (
+	startTime = time.Now()
+	images    *glutil.Images
+	eng       sprite.Engine
+	scene     *sprite.Node
+	game      *Game
+	menuScene *sprite.Node
+	menu      *Menu
+	screen    int                             // the screen shown; see screenMenu and friends
+	fingers   = make(map[touch.Sequence]bool) // the touches on the screen, whatever they do
+	touches   = make(map[touch.Sequence]int)  // the action each touch holds down
+	held      [sim.NumActions]int             // how many touches hold down each action
+)
+
+func onStart(glctx gl.Context) {
+	images = glutil.NewImages(glctx)
+	eng = glsprite.Engine(images)
+	game = NewGame(sim.Endless, sim.WithClips(&clips), sim.WithVibrator(buzz))
+	scene = game.Scene(eng)
+	menu = NewMenu(game)
+	menuScene = menu.Scene(eng)
+	// Forget touches from before the app was hidden.
+	fingers = make(map[touch.Sequence]bool)
+	touches = make(map[touch.Sequence]int)
+	held = [sim.NumActions]int{}
+	// Go straight back to a resumed run.
+	screen = screenMenu
+	if !game.Choosing() {
+		screen = screenGame
+	}
+	// Carry on from the game's clock, which is ahead
+	// of ours if the game resumed a saved run.
+	startTime = time.Now().Add(-time.Duration(game.Now()) * tick)
+	sim.SyncInBackground()
+	audio.resume()
+}
+
+func onStop() {
+	if tilting.on {
+		if err := sensor.Disable(sensor.Accelerometer); err != nil {
+			log.Print(err)
+		}
+		tilting.on = false
+	}
+	audio.pause()
+	game.Save()
+	sim.SyncInBackground()
+	eng.Release()
+	images.Release()
+	game = nil
+}
+
+func onPaint(glctx gl.Context, sz size.Event) {
+	glctx.ClearColor(1, 1, 1, 1)
+	glctx.Clear(gl.COLOR_BUFFER_BIT)
+	if game.Paused() {
+		// Hold the clock still while paused.
+		startTime = time.Now().Add(-time.Duration(game.Now()) * tick)
+	}
+	elapsed := time.Since(startTime)
+	now := clock.Time(elapsed / tick)
+	game.Resize(sz)
+	updateTilt()
+	if assetsChanged() {
+		game.ReloadAssets(eng)
+	}
+	audio.update(time.Now(), game)
+	if screen == screenMenu {
+		if menu.Done() {
+			screen = screenGame
+		}
+		eng.Render(menuScene, now, sz)
+	} else {
+		bindGestures(gestures.poll(time.Now()))
+		game.Update(now)
+		for _, s := range game.Sounds() {
+			audio.play(s)
+		}
+		game.Interpolate(float32(elapsed%tick) / float32(tick))
+		eng.Render(scene, now, sz)
+		// Record the run for sharing, up until its summary.
+		if !game.Choosing() && !game.Paused() && !game.GameOver() && now%clipEvery == 0 {
+			clips.record(glctx, sz)
+		}
+	}
+	if screenshotWanted {
+		screenshotWanted = false
+		name, err := saveScreenshot(glctx, sz)
+		if err != nil {
+			log.Print(err)
+		} else {
+			log.Print("saved screenshot ", name)
+		}
+	}
+}
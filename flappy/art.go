@@ -0,0 +1,580 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build darwin linux
+
+package flappy
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"log"
+	"math"
+
+	"github.com/adg/game/sim"
+	"golang.org/x/mobile/exp/sprite"
+)
+
+// The sprites in sprite.png are hand drawn. Everything else
+// is painted by the functions below when the textures are loaded.
+
+const cellSize = 64 // size of each painted sprite, in pixels
+
+const outline = 3 // width of the black outline around painted shapes
+
+var (
+	black = color.RGBA{0x00, 0x00, 0x00, 0xff}
+	grey  = color.RGBA{0x9a, 0x9a, 0x9a, 0xff}
+	brown = color.RGBA{0x8b, 0x5a, 0x2b, 0xff}
+	tan   = color.RGBA{0xd2, 0xa6, 0x79, 0xff}
+	green = color.RGBA{0x2e, 0x9e, 0x3e, 0xff}
+	gold  = color.RGBA{0xf5, 0xc5, 0x18, 0xff}
+	amber = color.RGBA{0xd8, 0x9a, 0x0b, 0xff}
+	sky   = color.RGBA{0x8f, 0xd3, 0xf5, 0xff}
+	white = color.RGBA{0xff, 0xff, 0xff, 0xff}
+	red   = color.RGBA{0xe0, 0x30, 0x30, 0xff}
+	blue  = color.RGBA{0x3a, 0x6e, 0xd8, 0xff}
+	plum  = color.RGBA{0x5a, 0x3a, 0x6e, 0xff}
+)
+
+// painters paint the textures that aren't in sprite.png
+// into the rectangle r of m.
+var painters = map[int]func(m *image.RGBA, r image.Rectangle){
+	sim.TexRock: paintRock,
+	sim.TexLog:  paintLog,
+	sim.TexPipe: paintPipe,
+	sim.TexCoin: paintCoin,
+
+	sim.TexSuperJump:   paintSuperJump,
+	sim.TexDoubleCoins: paintDoubleCoins,
+	sim.TexMagnet:      paintMagnet,
+	sim.TexAura:        paintAura,
+	sim.TexShield:      paintShield,
+	sim.TexRing:        paintRing,
+	sim.TexShard:       paintShard,
+	sim.TexSlowMo:      paintSlowMo,
+	sim.TexStar:        paintStar,
+
+	sim.TexSky: paintSky,
+
+	sim.TexDash:  paintDash,
+	sim.TexShade: paintShade,
+
+	sim.TexPlatform: paintPlatform,
+	sim.TexSpring:   paintSpring,
+	sim.TexBird:     paintBird,
+	sim.TexBat:      paintBat,
+	sim.TexSpikes:   paintSpikes,
+	sim.TexLava:     paintLava,
+	sim.TexIce:      paintIce,
+	sim.TexMole:     paintMole,
+	sim.TexLeaf:     paintLeaf,
+	sim.TexAcorn:    paintAcorn,
+	sim.TexCap:      paintCap,
+	sim.TexTopHat:   paintTopHat,
+	sim.TexCrown:    paintCrown,
+	sim.TexFlag:     paintFlag,
+	sim.TexCloud:    paintCloud,
+	sim.TexHills:    paintHills,
+	sim.TexGrass:    paintGrass,
+	sim.TexDust:     paintDust,
+	sim.TexDebris:   paintDebris,
+	sim.TexFeather:  paintFeather,
+	sim.TexFade:     paintFade,
+	sim.TexLight1:   paintLight(1),
+	sim.TexLight2:   paintLight(2),
+	sim.TexLight3:   paintLight(3),
+	sim.TexLight4:   paintLight(4),
+	sim.TexTree:     paintTree,
+	sim.TexPebbles:  paintPebbles,
+	sim.TexFlowers:  paintFlowers,
+	sim.TexFence:    paintFence,
+	sim.TexBalloon:  paintBalloon,
+	sim.TexWater:    paintWater,
+
+	sim.TexFlashWhite: paintGradient(white),
+	sim.TexFlashRed:   paintGradient(red),
+}
+
+// paintTextures paints the textures from sim.TexRock up to sim.TexCount
+// and returns their sub-textures in order.
+// The sky and hazards are painted in the style st.
+func paintTextures(eng sprite.Engine, st sim.Style) []sprite.SubTex {
+	const first = sim.TexRock
+	m := image.NewRGBA(image.Rect(0, 0, cellSize*(sim.TexCount-first), cellSize))
+	cell := func(i int) image.Rectangle {
+		return image.Rect(cellSize*(i-first), 0, cellSize*(i-first+1), cellSize)
+	}
+	for i := first; i < sim.TexCount; i++ {
+		painters[i](m, cell(i))
+	}
+	tintRect(m, cell(sim.TexSky), st.Theme.Tint)
+	if st.HighContrast {
+		tintRect(m, cell(sim.TexSky), mutedSky)
+	}
+	if st.Colorblind {
+		for _, i := range hazards {
+			hatch(m, cell(i))
+		}
+	}
+	t, err := eng.LoadTexture(m)
+	if err != nil {
+		log.Fatal(err)
+	}
+	var texs []sprite.SubTex
+	for i := first; i < sim.TexCount; i++ {
+		// Inset by a pixel so that neighbouring cells don't bleed in.
+		texs = append(texs, sprite.SubTex{t, image.Rect(cellSize*(i-first)+1, 1, cellSize*(i-first+1)-1, cellSize-1)})
+	}
+	return texs
+}
+
+func paintRock(m *image.RGBA, r image.Rectangle) {
+	r.Min.Y += r.Dy() / 4
+	outlined(m, r, grey, fillEllipse)
+}
+
+func paintLog(m *image.RGBA, r image.Rectangle) {
+	outlined(m, r, brown, fillEllipse)
+	outlined(m, r.Inset(r.Dx()/4), tan, fillEllipse)
+	fillEllipse(m, r.Inset(r.Dx()*7/16), brown)
+}
+
+func paintPipe(m *image.RGBA, r image.Rectangle) {
+	outlined(m, r.Inset(r.Dx()/8), green, fillRect)
+	lip := r
+	lip.Min.Y = lip.Max.Y - r.Dy()/4
+	outlined(m, lip, green, fillRect)
+}
+
+func paintCoin(m *image.RGBA, r image.Rectangle) {
+	outlined(m, r, gold, fillEllipse)
+	fillEllipse(m, r.Inset(r.Dx()/4), amber)
+	fillEllipse(m, r.Inset(r.Dx()/4+outline), gold)
+}
+
+func paintSuperJump(m *image.RGBA, r image.Rectangle) {
+	outlined(m, r, sky, fillEllipse)
+	d := r.Dx() / 8
+	c := r.Min.X + r.Dx()/2
+	fillPolygon(m, []image.Point{
+		{c, r.Min.Y + d*2},
+		{c + d*2, r.Min.Y + d*4},
+		{c - d*2, r.Min.Y + d*4},
+	}, white)
+	fillRect(m, image.Rect(c-d/2, r.Min.Y+d*4, c+d/2, r.Max.Y-d*2), white)
+}
+
+func paintDoubleCoins(m *image.RGBA, r image.Rectangle) {
+	outlined(m, r, sky, fillEllipse)
+	d := r.Dx() / 8
+	outlined(m, image.Rect(r.Min.X+d*3, r.Min.Y+d*2, r.Max.X-d, r.Max.Y-d*2), gold, fillEllipse)
+	outlined(m, image.Rect(r.Min.X+d, r.Min.Y+d*2, r.Max.X-d*3, r.Max.Y-d*2), gold, fillEllipse)
+}
+
+func paintMagnet(m *image.RGBA, r image.Rectangle) {
+	outlined(m, r, sky, fillEllipse)
+	d := r.Dx() / 8
+	// A horseshoe, open at the bottom.
+	outlined(m, image.Rect(r.Min.X+d*2, r.Min.Y+d*2, r.Max.X-d*2, r.Max.Y-d*2), red, fillEllipse)
+	fillEllipse(m, image.Rect(r.Min.X+d*3+outline, r.Min.Y+d*3+outline, r.Max.X-d*3-outline, r.Max.Y-d*3-outline), sky)
+	fillRect(m, image.Rect(r.Min.X+d*2, r.Min.Y+d*4, r.Max.X-d*2, r.Max.Y-d), sky)
+	for _, x := range []int{r.Min.X + d*2, r.Max.X - d*3 - outline} {
+		outlined(m, image.Rect(x, r.Min.Y+d*4, x+d+outline, r.Max.Y-d*3), red, fillRect)
+		outlined(m, image.Rect(x, r.Max.Y-d*3, x+d+outline, r.Max.Y-d*2), white, fillRect)
+	}
+}
+
+// paintAura paints a faint ring, drawn around the gopher while it is magnetised.
+func paintAura(m *image.RGBA, r image.Rectangle) {
+	fillEllipse(m, r, color.RGBA{0x40, 0x10, 0x10, 0x40})
+	fillEllipse(m, r.Inset(outline), color.RGBA{0x18, 0x06, 0x06, 0x18})
+}
+
+func paintShield(m *image.RGBA, r image.Rectangle) {
+	outlined(m, r, sky, fillEllipse)
+	d := r.Dx() / 8
+	shape := func(r image.Rectangle) []image.Point {
+		return []image.Point{
+			{r.Min.X, r.Min.Y},
+			{r.Max.X, r.Min.Y},
+			{r.Max.X, r.Min.Y + r.Dy()/2},
+			{r.Min.X + r.Dx()/2, r.Max.Y},
+			{r.Min.X, r.Min.Y + r.Dy()/2},
+		}
+	}
+	body := image.Rect(r.Min.X+d*2, r.Min.Y+d*2, r.Max.X-d*2, r.Max.Y-d)
+	fillPolygon(m, shape(body), black)
+	fillPolygon(m, shape(body.Inset(outline)), blue)
+}
+
+// paintRing paints the bubble drawn around a shielded gopher.
+func paintRing(m *image.RGBA, r image.Rectangle) {
+	fillEllipse(m, r, color.RGBA{0x1d, 0x37, 0x6c, 0x80})
+	fillEllipse(m, r.Inset(outline), color.RGBA{0x08, 0x14, 0x20, 0x24})
+}
+
+// paintShard paints a piece of a broken shield.
+func paintShard(m *image.RGBA, r image.Rectangle) {
+	fillPolygon(m, []image.Point{
+		{r.Min.X, r.Max.Y},
+		{r.Min.X + r.Dx()/3, r.Min.Y},
+		{r.Max.X, r.Min.Y + r.Dy()*2/3},
+	}, color.RGBA{0x2c, 0x52, 0xa2, 0xc0})
+}
+
+func paintSlowMo(m *image.RGBA, r image.Rectangle) {
+	outlined(m, r, sky, fillEllipse)
+	d := r.Dx() / 8
+	// An hourglass.
+	fillRect(m, image.Rect(r.Min.X+d*2, r.Min.Y+d*2-outline, r.Max.X-d*2, r.Min.Y+d*2), black)
+	fillRect(m, image.Rect(r.Min.X+d*2, r.Max.Y-d*2, r.Max.X-d*2, r.Max.Y-d*2+outline), black)
+	for j, c := range []color.RGBA{black, tan} {
+		i := j * outline
+		fillPolygon(m, []image.Point{
+			{r.Min.X + d*2 + i, r.Min.Y + d*2 + i/2},
+			{r.Max.X - d*2 - i, r.Min.Y + d*2 + i/2},
+			{r.Min.X + d*4, r.Min.Y + d*4 - i/2},
+		}, c)
+		fillPolygon(m, []image.Point{
+			{r.Min.X + d*4, r.Min.Y + d*4 + i/2},
+			{r.Max.X - d*2 - i, r.Max.Y - d*2 - i/2},
+			{r.Min.X + d*2 + i, r.Max.Y - d*2 - i/2},
+		}, c)
+	}
+}
+
+func paintStar(m *image.RGBA, r image.Rectangle) {
+	cx, cy := float64(r.Min.X+r.Max.X)/2, float64(r.Min.Y+r.Max.Y)/2
+	star := func(radius float64) []image.Point {
+		var pts []image.Point
+		for i := 0; i < 10; i++ {
+			d := radius
+			if i%2 == 1 {
+				d *= 0.45
+			}
+			a := math.Pi * (float64(i)/5 - 0.5)
+			pts = append(pts, image.Pt(int(cx+d*math.Cos(a)), int(cy+d*math.Sin(a))))
+		}
+		return pts
+	}
+	fillPolygon(m, star(float64(r.Dx())/2), black)
+	fillPolygon(m, star(float64(r.Dx())/2-outline*2), gold)
+}
+
+func paintDash(m *image.RGBA, r image.Rectangle) {
+	outlined(m, r, sky, fillEllipse)
+	d := r.Dx() / 8
+	c := r.Min.Y + r.Dy()/2
+	fillPolygon(m, []image.Point{
+		{r.Max.X - d*2, c},
+		{r.Max.X - d*4, c - d*2},
+		{r.Max.X - d*4, c + d*2},
+	}, white)
+	fillRect(m, image.Rect(r.Min.X+d*2, c-d/2, r.Max.X-d*4, c+d/2), white)
+}
+
+func paintPlatform(m *image.RGBA, r image.Rectangle) {
+	outlined(m, r, tan, fillRect)
+	for x := r.Min.X + r.Dx()/4; x < r.Max.X-outline; x += r.Dx() / 4 {
+		fillRect(m, image.Rect(x-1, r.Min.Y, x+1, r.Max.Y), black)
+	}
+}
+
+func paintSpring(m *image.RGBA, r image.Rectangle) {
+	// A coil beneath a pad.
+	coil := r
+	coil.Min.Y += r.Dy() / 4
+	d := r.Dx() / 8
+	for y := coil.Min.Y; y < coil.Max.Y-d/2; y += d {
+		outlined(m, image.Rect(coil.Min.X+d*2, y, coil.Max.X-d*2, y+d), grey, fillEllipse)
+	}
+	pad := r
+	pad.Max.Y = r.Min.Y + r.Dy()/3
+	outlined(m, pad, red, fillRect)
+}
+
+func paintBird(m *image.RGBA, r image.Rectangle) {
+	d := r.Dx() / 8
+	// Beak, pointing left towards the gopher.
+	fillPolygon(m, []image.Point{
+		{r.Min.X, r.Min.Y + d*4},
+		{r.Min.X + d*2, r.Min.Y + d*3},
+		{r.Min.X + d*2, r.Min.Y + d*5},
+	}, amber)
+	outlined(m, image.Rect(r.Min.X+d, r.Min.Y+d*2, r.Max.X-d, r.Max.Y-d*2), blue, fillEllipse)
+	// Wing.
+	fillPolygon(m, []image.Point{
+		{r.Min.X + d*3, r.Min.Y + d*4},
+		{r.Max.X - d*2, r.Min.Y},
+		{r.Max.X - d, r.Min.Y + d*4},
+	}, black)
+	fillEllipse(m, image.Rect(r.Min.X+d*2, r.Min.Y+d*3, r.Min.X+d*3, r.Min.Y+d*4), black)
+}
+
+func paintBat(m *image.RGBA, r image.Rectangle) {
+	d := r.Dx() / 8
+	c := r.Min.X + r.Dx()/2
+	// Wings.
+	for _, s := range []int{-1, 1} {
+		fillPolygon(m, []image.Point{
+			{c, r.Min.Y + d*3},
+			{c + s*d*4, r.Min.Y + d},
+			{c + s*d*3, r.Min.Y + d*4},
+			{c + s*d*4, r.Min.Y + d*6},
+			{c, r.Min.Y + d*5},
+		}, black)
+	}
+	outlined(m, image.Rect(c-d*2, r.Min.Y+d*2, c+d*2, r.Min.Y+d*6), plum, fillEllipse)
+	fillEllipse(m, image.Rect(c-d, r.Min.Y+d*3, c, r.Min.Y+d*4), white)
+}
+
+func paintSpikes(m *image.RGBA, r image.Rectangle) {
+	const n = 4
+	w := r.Dx() / n
+	for i := 0; i < n; i++ {
+		x := r.Min.X + i*w
+		fillPolygon(m, []image.Point{{x, r.Max.Y}, {x + w/2, r.Min.Y}, {x + w, r.Max.Y}}, black)
+		fillPolygon(m, []image.Point{{x + outline, r.Max.Y}, {x + w/2, r.Min.Y + outline*2}, {x + w - outline, r.Max.Y}}, grey)
+	}
+}
+
+func paintLava(m *image.RGBA, r image.Rectangle) {
+	fillRect(m, r, color.RGBA{0xf0, 0x60, 0x10, 0xff})
+	top := r
+	top.Max.Y = r.Min.Y + r.Dy()/4
+	fillRect(m, top, color.RGBA{0xff, 0xc0, 0x30, 0xff})
+	d := r.Dx() / 8
+	for _, p := range []image.Point{{2, 4}, {5, 3}, {6, 6}, {3, 7}} {
+		fillEllipse(m, image.Rect(r.Min.X+p.X*d-d/2, r.Min.Y+p.Y*d-d/2, r.Min.X+p.X*d+d/2, r.Min.Y+p.Y*d+d/2), color.RGBA{0xff, 0xc0, 0x30, 0xff})
+	}
+}
+
+func paintIce(m *image.RGBA, r image.Rectangle) {
+	fillRect(m, r, color.RGBA{0xb8, 0xe4, 0xf4, 0xff})
+	top := r
+	top.Max.Y = r.Min.Y + r.Dy()/8
+	fillRect(m, top, white)
+	d := r.Dx() / 8
+	for _, p := range []image.Point{{2, 3}, {5, 5}} {
+		fillPolygon(m, []image.Point{
+			{r.Min.X + p.X*d, r.Min.Y + p.Y*d},
+			{r.Min.X + (p.X+2)*d, r.Min.Y + (p.Y-1)*d},
+			{r.Min.X + (p.X+2)*d, r.Min.Y + p.Y*d - d/2},
+			{r.Min.X + p.X*d, r.Min.Y + p.Y*d + d/2},
+		}, white)
+	}
+}
+
+func paintMole(m *image.RGBA, r image.Rectangle) {
+	d := r.Dx() / 16
+	body := r
+	body.Min.Y += d * 2
+	body.Max.X -= d * 2
+	outlined(m, body, color.RGBA{0x4a, 0x3a, 0x32, 0xff}, fillEllipse)
+	// Claws, reaching for the gopher.
+	for i := 0; i < 3; i++ {
+		y := r.Min.Y + d*(9+i*2)
+		fillPolygon(m, []image.Point{{r.Max.X - d*5, y}, {r.Max.X, y + d/2}, {r.Max.X - d*5, y + d*2}}, white)
+	}
+	// Snout and eyes.
+	fillEllipse(m, image.Rect(r.Max.X-d*6, r.Min.Y+d*5, r.Max.X-d*2, r.Min.Y+d*8), color.RGBA{0xf0, 0x8a, 0xa0, 0xff})
+	fillEllipse(m, image.Rect(r.Max.X-d*8, r.Min.Y+d*4, r.Max.X-d*7, r.Min.Y+d*5), black)
+	fillEllipse(m, image.Rect(r.Max.X-d*11, r.Min.Y+d*4, r.Max.X-d*10, r.Min.Y+d*5), black)
+}
+
+func paintLeaf(m *image.RGBA, r image.Rectangle) {
+	d := r.Dx() / 8
+	fillPolygon(m, []image.Point{
+		{r.Min.X + d, r.Max.Y - d},
+		{r.Min.X + d, r.Min.Y + d*3},
+		{r.Min.X + d*4, r.Min.Y + d},
+		{r.Max.X - d, r.Min.Y + d},
+		{r.Max.X - d, r.Min.Y + d*4},
+		{r.Min.X + d*3, r.Max.Y - d},
+	}, black)
+	fillPolygon(m, []image.Point{
+		{r.Min.X + d*2, r.Max.Y - d*2},
+		{r.Min.X + d*2, r.Min.Y + d*3},
+		{r.Min.X + d*4, r.Min.Y + d*2},
+		{r.Max.X - d*2, r.Min.Y + d*2},
+		{r.Max.X - d*2, r.Min.Y + d*4},
+		{r.Min.X + d*3, r.Max.Y - d*2},
+	}, amber)
+	fillRect(m, image.Rect(r.Min.X+d*2, r.Max.Y-d*2, r.Min.X+d*3, r.Max.Y), brown)
+}
+
+func paintAcorn(m *image.RGBA, r image.Rectangle) {
+	d := r.Dx() / 8
+	outlined(m, image.Rect(r.Min.X+d, r.Min.Y+d*2, r.Max.X-d, r.Max.Y), amber, fillEllipse)
+	top := image.Rect(r.Min.X, r.Min.Y+d, r.Max.X, r.Min.Y+d*4)
+	outlined(m, top, brown, fillEllipse)
+	fillRect(m, image.Rect(r.Min.X+d*4-1, r.Min.Y, r.Min.X+d*4+2, r.Min.Y+d*2), black)
+}
+
+func paintCap(m *image.RGBA, r image.Rectangle) {
+	// The dome is cut off by the brim, so keep it inside r.
+	m = m.SubImage(r).(*image.RGBA)
+	d := r.Dx() / 8
+	outlined(m, image.Rect(r.Min.X+d, r.Min.Y+d*3, r.Max.X-d, r.Max.Y+d*3), red, fillEllipse)
+	fillRect(m, image.Rect(r.Min.X, r.Max.Y-d*2, r.Max.X, r.Max.Y), black)
+	fillRect(m, image.Rect(r.Min.X+outline, r.Max.Y-d*2+outline, r.Max.X-outline, r.Max.Y-outline), red)
+}
+
+func paintTopHat(m *image.RGBA, r image.Rectangle) {
+	d := r.Dx() / 8
+	felt := color.RGBA{0x30, 0x30, 0x38, 0xff}
+	outlined(m, image.Rect(r.Min.X+d*2, r.Min.Y, r.Max.X-d*2, r.Max.Y-d), felt, fillRect)
+	fillRect(m, image.Rect(r.Min.X+d*2+outline, r.Max.Y-d*3, r.Max.X-d*2-outline, r.Max.Y-d*2), red)
+	outlined(m, image.Rect(r.Min.X, r.Max.Y-d*2, r.Max.X, r.Max.Y), felt, fillRect)
+}
+
+func paintCrown(m *image.RGBA, r image.Rectangle) {
+	d := r.Dx() / 8
+	pts := []image.Point{
+		{r.Min.X, r.Max.Y},
+		{r.Min.X, r.Min.Y + d*2},
+		{r.Min.X + d*2, r.Min.Y + d*5},
+		{r.Min.X + d*4, r.Min.Y},
+		{r.Max.X - d*2, r.Min.Y + d*5},
+		{r.Max.X, r.Min.Y + d*2},
+		{r.Max.X, r.Max.Y},
+	}
+	fillPolygon(m, pts, black)
+	inner := make([]image.Point, len(pts))
+	for i, p := range pts {
+		// Shrink towards the centre of the bottom edge to leave an outline.
+		c := image.Pt(r.Min.X+r.Dx()/2, r.Max.Y)
+		inner[i] = c.Add(p.Sub(c).Mul(7).Div(8)).Sub(image.Pt(0, outline))
+	}
+	fillPolygon(m, inner, gold)
+}
+
+func paintFlag(m *image.RGBA, r image.Rectangle) {
+	d := r.Dx() / 8
+	outlined(m, image.Rect(r.Min.X+d, r.Min.Y, r.Min.X+d*2+outline, r.Max.Y), grey, fillRect)
+	for y := 0; y < 2; y++ {
+		for x := 0; x < 3; x++ {
+			c := white
+			if (x+y)%2 == 0 {
+				c = black
+			}
+			fillRect(m, image.Rect(r.Min.X+d*2+x*d*2, r.Min.Y+y*d*2, r.Min.X+d*4+x*d*2, r.Min.Y+d*2+y*d*2), c)
+		}
+	}
+}
+
+func paintBalloon(m *image.RGBA, r image.Rectangle) {
+	d := r.Dx() / 8
+	fillRect(m, image.Rect(r.Min.X+d*4-1, r.Min.Y+d*5, r.Min.X+d*4+1, r.Max.Y), black)
+	outlined(m, image.Rect(r.Min.X+d, r.Min.Y, r.Max.X-d, r.Min.Y+d*6), red, fillEllipse)
+	fillEllipse(m, image.Rect(r.Min.X+d*2+outline, r.Min.Y+d+outline, r.Min.X+d*3+outline, r.Min.Y+d*2+outline), white)
+}
+
+// paintWater paints the water of a pool, which the gopher is seen through.
+func paintWater(m *image.RGBA, r image.Rectangle) {
+	fillRect(m, r, color.RGBA{0x1d, 0x37, 0x6c, 0x80})
+	fillRect(m, image.Rect(r.Min.X, r.Min.Y, r.Max.X, r.Min.Y+r.Dy()/8), color.RGBA{0x60, 0x80, 0xa0, 0xa0})
+}
+
+func paintCloud(m *image.RGBA, r image.Rectangle) {
+	d := r.Dx() / 8
+	fillEllipse(m, image.Rect(r.Min.X+d, r.Min.Y+d*3, r.Min.X+d*4, r.Max.Y-d), white)
+	fillEllipse(m, image.Rect(r.Min.X+d*2, r.Min.Y+d*2, r.Min.X+d*6, r.Max.Y-d*2), white)
+	fillEllipse(m, image.Rect(r.Min.X+d*4, r.Min.Y+d*3, r.Max.X-d, r.Max.Y-d), white)
+}
+
+// paintHills paints distant hills, faded towards the sky,
+// meeting the cell edges at the same height so that they repeat.
+func paintHills(m *image.RGBA, r image.Rectangle) {
+	haze := color.RGBA{0x7a, 0xb8, 0x8a, 0xff}
+	for x := r.Min.X; x < r.Max.X; x++ {
+		a := 2 * math.Pi * float64(x-r.Min.X) / float64(r.Dx())
+		h := 0.55 + 0.25*math.Sin(a) + 0.1*math.Sin(3*a)
+		top := r.Max.Y - int(h*float64(r.Dy()))
+		fillRect(m, image.Rect(x, top, x+1, r.Max.Y), haze)
+	}
+}
+
+func paintGrass(m *image.RGBA, r image.Rectangle) {
+	d := r.Dx() / 8
+	dark := color.RGBA{0x1e, 0x6e, 0x2a, 0xff}
+	for i, x := 0, r.Min.X; x+d <= r.Max.X; i, x = i+1, x+d {
+		top := r.Min.Y + d*(i%3)
+		fillPolygon(m, []image.Point{{x, r.Max.Y}, {x + d/2, top}, {x + d, r.Max.Y}}, dark)
+	}
+}
+
+func paintDust(m *image.RGBA, r image.Rectangle) {
+	fillEllipse(m, r, color.RGBA{0x6a, 0x58, 0x46, 0xa0})
+}
+
+func paintDebris(m *image.RGBA, r image.Rectangle) {
+	d := r.Dx() / 8
+	fillPolygon(m, []image.Point{{r.Min.X + d, r.Max.Y - d}, {r.Min.X + d*3, r.Min.Y + d}, {r.Max.X - d, r.Min.Y + d*3}, {r.Max.X - d*2, r.Max.Y - d}}, black)
+	fillPolygon(m, []image.Point{{r.Min.X + d*2, r.Max.Y - d*2}, {r.Min.X + d*3, r.Min.Y + d*2}, {r.Max.X - d*2, r.Min.Y + d*3}, {r.Max.X - d*3, r.Max.Y - d*2}}, brown)
+}
+
+func paintFeather(m *image.RGBA, r image.Rectangle) {
+	d := r.Dx() / 8
+	outlined(m, image.Rect(r.Min.X+d*3, r.Min.Y, r.Max.X-d*3, r.Max.Y-d), sky, fillEllipse)
+	fillRect(m, image.Rect(r.Min.X+d*4-1, r.Min.Y+d*2, r.Min.X+d*4+1, r.Max.Y), black)
+}
+
+// paintShade paints a translucent black, for darkening things drawn beneath it.
+func paintShade(m *image.RGBA, r image.Rectangle) {
+	fillRect(m, r, color.RGBA{0, 0, 0, 0x80})
+}
+
+// outlined paints a shape of colour c in r with a black outline.
+func outlined(m *image.RGBA, r image.Rectangle, c color.Color, fill func(*image.RGBA, image.Rectangle, color.Color)) {
+	fill(m, r, black)
+	fill(m, r.Inset(outline), c)
+}
+
+func fillRect(m *image.RGBA, r image.Rectangle, c color.Color) {
+	draw.Draw(m, r, &image.Uniform{c}, image.ZP, draw.Src)
+}
+
+// fillPolygon fills the polygon with vertices pts.
+func fillPolygon(m *image.RGBA, pts []image.Point, c color.Color) {
+	var b image.Rectangle
+	for i, p := range pts {
+		if i == 0 {
+			b = image.Rectangle{p, p}
+		}
+		b = b.Union(image.Rectangle{p, p.Add(image.Pt(1, 1))})
+	}
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			// Count crossings of a ray cast rightwards from the pixel centre.
+			px, py := float64(x)+0.5, float64(y)+0.5
+			in := false
+			for i, p := range pts {
+				q := pts[(i+1)%len(pts)]
+				x0, y0, x1, y1 := float64(p.X), float64(p.Y), float64(q.X), float64(q.Y)
+				if (y0 > py) != (y1 > py) && px < x0+(py-y0)*(x1-x0)/(y1-y0) {
+					in = !in
+				}
+			}
+			if in {
+				m.Set(x, y, c)
+			}
+		}
+	}
+}
+
+func fillEllipse(m *image.RGBA, r image.Rectangle, c color.Color) {
+	cx, cy := float64(r.Min.X+r.Max.X)/2, float64(r.Min.Y+r.Max.Y)/2
+	rx, ry := float64(r.Dx())/2, float64(r.Dy())/2
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			dx, dy := (float64(x)+0.5-cx)/rx, (float64(y)+0.5-cy)/ry
+			if dx*dx+dy*dy <= 1 {
+				m.Set(x, y, c)
+			}
+		}
+	}
+}
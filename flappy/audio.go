@@ -0,0 +1,210 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build darwin linux
+
+package flappy
+
+import (
+	"log"
+	"math"
+	"time"
+
+	"github.com/adg/game/sim"
+	"golang.org/x/mobile/exp/audio/al"
+)
+
+const (
+	sfxVoices = 8                      // sound effects that may play at once
+	duckLevel = 0.3                    // how loud ducked music is
+	duckTime  = 500 * time.Millisecond // how long the music takes to duck, and to come back
+)
+
+// soundEffects are the sound effects; see loadSoundEffects.
+var soundEffects = []string{sim.SfxJump, sim.SfxFlap, sim.SfxLand, sim.SfxCoin, sim.SfxCrash, sim.SfxPowerUp, sim.SfxEnemy, sim.SfxObstacle, sim.SfxHit, sim.SfxMilestone, sim.SfxBest}
+
+// openSound opens the audio asset called name.wav, whose samples
+// may be compressed with IMA ADPCM; see decodeADPCM.
+func openSound(name string) (pcmStream, error) {
+	s, err := openWAV(name + ".wav")
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// OpenAL source parameters the al package doesn't name.
+const (
+	alPitch  = 0x1003 // playback rate, from 0.5 to 2
+	alBuffer = 0x1009 // the buffer to play
+)
+
+// A mixer owns the audio device, and plays the music and sound effects
+// at the volumes the player has chosen.
+type mixer struct {
+	on      bool                    // is the audio device open?
+	music   musicPlayer             // the soundtrack
+	effects map[string]loadedEffect // the sound effects, by name
+	voices  []al.Source             // sources the sound effects take turns to play through
+	next    int                     // the voice to play the next sound effect
+	sfxGain float32                 // gain of the sound effects, master volume included
+	duck    ramp                    // how loud the music is, ducked or not
+	last    time.Time               // when the mixer was last updated
+
+	hidden    bool // is the app hidden?
+	unplugged bool // were headphones unplugged since the player last pressed anything?
+	quiet     bool // are the sounds paused, for either reason?
+}
+
+// A ramp moves a gain smoothly towards its target,
+// so that sounds fade rather than cut in and out.
+type ramp struct {
+	level, target float32
+	time          time.Duration // how long the gain takes to ramp from 0 to 1
+}
+
+// step moves the gain towards its target over the time dt.
+func (r *ramp) step(dt time.Duration) {
+	d := float32(dt) / float32(r.time)
+	switch {
+	case r.level < r.target:
+		if r.level += d; r.level > r.target {
+			r.level = r.target
+		}
+	case r.level > r.target:
+		if r.level -= d; r.level < r.target {
+			r.level = r.target
+		}
+	}
+}
+
+// audio mixes the game's sound.
+var audio mixer
+
+// start opens the audio device and loads the sound effects.
+// Without an audio device, the game is silent.
+func (a *mixer) start() {
+	if err := al.OpenDevice(); err != nil {
+		log.Print(err)
+		return
+	}
+	a.on = true
+	a.duck = ramp{level: 1, target: 1, time: duckTime}
+	a.last = time.Now()
+	a.music.start()
+	a.effects = make(map[string]loadedEffect)
+	for name, e := range loadSoundEffects() {
+		a.effects[name] = loadEffect(e)
+	}
+	a.voices = al.GenSources(sfxVoices)
+}
+
+// stop releases the sounds and closes the audio device.
+func (a *mixer) stop() {
+	if !a.on {
+		return
+	}
+	a.music.stop()
+	al.StopSources(a.voices...)
+	al.DeleteSources(a.voices...)
+	for _, e := range a.effects {
+		al.DeleteBuffers(e.takes...)
+	}
+	al.CloseDevice()
+	*a = mixer{hidden: a.hidden, unplugged: a.unplugged, quiet: a.quiet}
+}
+
+// pause pauses the sound while the app is hidden.
+func (a *mixer) pause() {
+	a.hidden = true
+	a.hush()
+}
+
+// resume carries on with the sound when the app is shown again.
+func (a *mixer) resume() {
+	a.hidden = false
+	a.hush()
+}
+
+// unplug pauses the sound when headphones are unplugged,
+// so that it doesn't suddenly come out of the speaker.
+func (a *mixer) unplug() {
+	a.unplugged = true
+	a.hush()
+}
+
+// unmute carries on with the sound paused by unplug,
+// once the player presses something.
+func (a *mixer) unmute() {
+	if a.unplugged {
+		a.unplugged = false
+		a.hush()
+	}
+}
+
+// hush pauses or resumes the sound, as the app being hidden
+// or headphones being unplugged require. The music carries on from
+// where it was paused.
+func (a *mixer) hush() {
+	quiet := a.hidden || a.unplugged
+	if quiet == a.quiet {
+		return
+	}
+	a.quiet = quiet
+	if !a.on {
+		return
+	}
+	if quiet {
+		al.StopSources(a.voices...)
+		a.music.pause()
+	} else {
+		a.music.resume()
+	}
+}
+
+// update makes the sound follow g as of now: it plays the track g wants,
+// at the volumes and pitch g asks for, ducking the music or bringing it back.
+func (a *mixer) update(now time.Time, g *Game) {
+	if !a.on {
+		return
+	}
+	dt := now.Sub(a.last)
+	a.last = now
+	if a.quiet {
+		return
+	}
+	a.duck.target = 1
+	if g.Ducked() {
+		a.duck.target = duckLevel
+	}
+	a.duck.step(dt)
+	s := g.Settings()
+	master := float32(s.Volume) / sim.MaxVolume
+	a.sfxGain = master * float32(s.SfxVolume) / sim.MaxVolume
+	a.music.play(g.Music())
+	a.music.update(dt, master*float32(s.MusicVolume)/sim.MaxVolume*a.duck.level, g.MusicPitch())
+}
+
+// play plays the sound effect s, cutting short
+// the oldest sound effect if all the voices are busy.
+func (a *mixer) play(s sim.Sound) {
+	if !a.on || a.quiet || a.sfxGain == 0 {
+		return
+	}
+	e, ok := a.effects[s.Name]
+	if !ok || len(e.takes) == 0 {
+		return
+	}
+	b, pitch := e.pick()
+	v := a.voices[a.next]
+	a.next = (a.next + 1) % len(a.voices)
+	al.StopSources(v)
+	v.Seti(alBuffer, int32(b))
+	v.SetGain(a.sfxGain)
+	v.Setf(alPitch, pitch)
+	// Panning places the sound on a circle around the listener,
+	// who faces away along the z-axis, so that it is as loud at any pan.
+	v.SetPosition(al.Vector{s.Pan, 0, -float32(math.Sqrt(float64(1 - s.Pan*s.Pan)))})
+	al.PlaySources(v)
+}
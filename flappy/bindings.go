@@ -0,0 +1,186 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build darwin linux
+
+package flappy
+
+import (
+	"github.com/adg/game/sim"
+	"golang.org/x/mobile/event/key"
+)
+
+// keyCodes are the keys that may be bound, by name.
+var keyCodes = map[string]key.Code{
+	"Space":  key.CodeSpacebar,
+	"Return": key.CodeReturnEnter,
+	"Tab":    key.CodeTab,
+	"Escape": key.CodeEscape,
+	"Up":     key.CodeUpArrow,
+	"Down":   key.CodeDownArrow,
+	"Left":   key.CodeLeftArrow,
+	"Right":  key.CodeRightArrow,
+	"F3":     key.CodeF3,
+	"F12":    key.CodeF12,
+}
+
+func init() {
+	for c := 'A'; c <= 'Z'; c++ {
+		keyCodes[string(c)] = key.CodeA + key.Code(c-'A')
+	}
+}
+
+// padNames name the gamepad buttons; see padA and friends.
+var padNames = [...]string{
+	padA:     "A",
+	padB:     "B",
+	padX:     "X",
+	padRight: "Right",
+	padLeft:  "Left",
+	padStart: "Start",
+}
+
+// gestureNames name the gestures; see gestureTap and friends.
+var gestureNames = [...]string{
+	gestureTap:          "tap",
+	gestureHold:         "hold",
+	gestureSwipeUp:      "swipe-up",
+	gestureSwipeDown:    "swipe-down",
+	gestureSwipeForward: "swipe-forward",
+	gestureSwipeBack:    "swipe-back",
+}
+
+// keyName returns the name of the key c, and whether it may be bound.
+func keyName(c key.Code) (string, bool) {
+	for name, k := range keyCodes {
+		if k == c {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// boundActions returns the actions whose inputs, as picked from
+// their bindings by inputs, include the one named name.
+func boundActions(inputs func(sim.Binding) []string, name string) []int {
+	s := game.Settings()
+	var as []int
+	for a := 0; a < sim.NumActions; a++ {
+		for _, n := range inputs(s.Binding(a)) {
+			if n == name {
+				as = append(as, a)
+				break
+			}
+		}
+	}
+	return as
+}
+
+// keyActions returns the actions the key c does.
+func keyActions(c key.Code) []int {
+	name, ok := keyName(c)
+	if !ok {
+		return nil
+	}
+	return boundActions(func(b sim.Binding) []string { return b.Keys }, name)
+}
+
+// buttonActions returns the actions the gamepad button does.
+func buttonActions(button int) []int {
+	return boundActions(func(b sim.Binding) []string { return b.Buttons }, padNames[button])
+}
+
+// gestureActions returns the actions the gesture does.
+func gestureActions(gesture int) []int {
+	return boundActions(func(b sim.Binding) []string { return b.Gestures }, gestureNames[gesture])
+}
+
+// doActions presses or releases each of the actions as,
+// on whichever screen is shown when they start.
+func doActions(as []int, down bool) {
+	title := screen == screenMenu
+	for _, a := range as {
+		if title {
+			titleAction(a, down)
+		} else {
+			gameAction(a, down)
+		}
+	}
+}
+
+// titleAction does action a on the title screen.
+func titleAction(a int, down bool) {
+	if !down {
+		return
+	}
+	switch a {
+	case sim.ActionJump:
+		menu.Press()
+	case sim.ActionNext:
+		menu.Cycle(+1)
+	case sim.ActionPrevious:
+		menu.Cycle(-1)
+	case sim.ActionBack:
+		menu.Back()
+	}
+}
+
+// gameAction presses or releases action a during a run,
+// and on the screens shown around it.
+func gameAction(a int, down bool) {
+	switch a {
+	case sim.ActionJump:
+		game.Press(down)
+		return
+	case sim.ActionSlide:
+		game.Slide(down)
+		return
+	case sim.ActionThrow:
+		game.Throw(down)
+		return
+	}
+	if !down {
+		return
+	}
+	menus := game.Choosing() || game.Paused() || game.GameOver()
+	switch a {
+	case sim.ActionDash:
+		if !menus {
+			game.Dash()
+		}
+	case sim.ActionNext:
+		if menus {
+			game.Cycle(+1)
+		}
+	case sim.ActionPrevious:
+		game.Cycle(-1)
+	case sim.ActionBack:
+		switch {
+		case game.Shopping():
+			game.CloseShop()
+		case game.Choosing():
+			screen = screenMenu
+		}
+	case sim.ActionPause:
+		game.Pause()
+	case sim.ActionShop:
+		if game.Shopping() {
+			game.CloseShop()
+		} else {
+			game.OpenShop()
+		}
+	case sim.ActionMode:
+		game.CycleMode()
+	case sim.ActionDaily:
+		if game.Choosing() {
+			game.SetDaily(!game.Daily())
+		}
+	case sim.ActionRefund:
+		game.RefundSelected()
+	case sim.ActionDebug:
+		game.ToggleDebug()
+	case sim.ActionScreenshot:
+		TakeScreenshot()
+	}
+}
@@ -0,0 +1,69 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build darwin linux
+
+package flappy
+
+import (
+	"image"
+	"image/draw"
+	"log"
+
+	"github.com/adg/game/sim"
+	"golang.org/x/mobile/exp/sprite"
+)
+
+// groundSetSize is the number of textures in a biome's ground set.
+const groundSetSize = sim.TexEarth - sim.TexGround1 + 1
+
+// loadGroundSets loads a set of ground and earth textures for each biome,
+// by day and by night, to be appended to texs after sim.TexCount.
+func loadGroundSets(eng sprite.Engine, m image.Image, texs []sprite.SubTex) []sprite.SubTex {
+	// Only the ground and earth part of the atlas is needed.
+	r := texs[sim.TexGround1].R.Union(texs[sim.TexEarth].R)
+	ground := image.NewRGBA(image.Rect(0, 0, r.Dx(), r.Dy()))
+	draw.Draw(ground, ground.Bounds(), m, r.Min, draw.Src)
+
+	var sets []sprite.SubTex
+	for _, b := range sim.Biomes {
+		for _, night := range []bool{false, true} {
+			tint := b.Tint
+			if night {
+				tint = func(r, g, bl float32) (float32, float32, float32) {
+					r, g, bl = b.Tint(r, g, bl)
+					return r * 0.4, g * 0.4, bl * 0.5
+				}
+			}
+			t, err := eng.LoadTexture(recolor(ground, tint))
+			if err != nil {
+				log.Fatal(err)
+			}
+			for x := sim.TexGround1; x <= sim.TexEarth; x++ {
+				sets = append(sets, sprite.SubTex{t, texs[x].R.Sub(r.Min)})
+			}
+		}
+	}
+	return sets
+}
+
+// recolor returns a copy of m with tint applied to each pixel.
+func recolor(m *image.RGBA, tint func(r, g, b float32) (float32, float32, float32)) image.Image {
+	d := image.NewRGBA(m.Bounds())
+	clamp := func(v float32, a uint8) uint8 {
+		if v > float32(a) {
+			return a
+		}
+		return uint8(v)
+	}
+	for i := 0; i < len(m.Pix); i += 4 {
+		p := m.Pix[i : i+4 : i+4]
+		r, g, b := tint(float32(p[0]), float32(p[1]), float32(p[2]))
+		d.Pix[i+0] = clamp(r, p[3])
+		d.Pix[i+1] = clamp(g, p[3])
+		d.Pix[i+2] = clamp(b, p[3])
+		d.Pix[i+3] = p[3]
+	}
+	return d
+}
@@ -0,0 +1,51 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build darwin linux
+
+package flappy
+
+import (
+	"image"
+	"image/draw"
+	"log"
+
+	"github.com/adg/game/sim"
+	"golang.org/x/mobile/exp/sprite"
+)
+
+// characterSets is the first of the characters' gopher textures,
+// which follow the trail's ghosts.
+const characterSets = trailSets + trailLength*sim.GopherSetSize
+
+// loadCharacterSets loads a set of gopher textures for each character
+// after the first, which uses the gopher textures as they are.
+func loadCharacterSets(eng sprite.Engine, m image.Image, texs []sprite.SubTex) []sprite.SubTex {
+	r := texs[sim.TexGopherRun1].R
+	for x := sim.TexGopherRun1; x <= sim.TexGopherGlide; x++ {
+		r = r.Union(texs[x].R)
+	}
+	gopher := image.NewRGBA(image.Rect(0, 0, r.Dx(), r.Dy()))
+	draw.Draw(gopher, gopher.Bounds(), m, r.Min, draw.Src)
+
+	var sets []sprite.SubTex
+	for _, c := range sim.Characters[1:] {
+		t, err := eng.LoadTexture(recolor(gopher, c.Tint))
+		if err != nil {
+			log.Fatal(err)
+		}
+		for x := sim.TexGopherRun1; x <= sim.TexGopherGlide; x++ {
+			sets = append(sets, sprite.SubTex{t, texs[x].R.Sub(r.Min)})
+		}
+	}
+	return sets
+}
+
+// gopherTex returns the chosen character's variant of gopher texture x.
+func (g *Game) gopherTex(x int) int {
+	if g.Character == 0 {
+		return x
+	}
+	return characterSets + (g.Character-1)*sim.GopherSetSize + x - sim.TexGopherRun1
+}
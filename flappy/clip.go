@@ -0,0 +1,149 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build darwin linux
+
+package flappy
+
+import (
+	"image"
+	"image/color/palette"
+	"image/draw"
+	"image/gif"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/adg/game/sim"
+	"golang.org/x/mobile/event/size"
+	"golang.org/x/mobile/gl"
+)
+
+const (
+	clipEvery  = 4                                                 // clock frames between frames of a clip
+	clipLength = 10 * sim.ClockRate / clipEvery                    // frames in a clip: the last ten seconds
+	clipWidth  = 240                                               // width of a clip, in pixels
+	clipDelay  = (clipEvery*100 + sim.ClockRate/2) / sim.ClockRate // time each frame of a clip is shown, in hundredths of a second
+)
+
+// A clipRecorder keeps the last few seconds of a run,
+// shrunk down, so that they can be shared as an animated GIF.
+type clipRecorder struct {
+	frames [clipLength]*image.RGBA // a ring of the frames recorded
+	next   int                     // index in frames of the next to be recorded
+	n      int                     // number of frames recorded
+
+	mu     sync.Mutex
+	status string // what became of the last clip saved
+}
+
+// clips records the run being played.
+var clips clipRecorder
+
+// Reset forgets the frames recorded, ready for a new run.
+func (c *clipRecorder) Reset() {
+	c.frames = [clipLength]*image.RGBA{}
+	c.next, c.n = 0, 0
+	c.setStatus("")
+}
+
+// record reads the frame just drawn from the framebuffer
+// and keeps a shrunk copy of it.
+func (c *clipRecorder) record(glctx gl.Context, sz size.Event) {
+	w, h := sz.WidthPx, sz.HeightPx
+	if w < clipWidth || h == 0 {
+		return
+	}
+	pix := make([]byte, w*h*4)
+	glctx.ReadPixels(pix, 0, 0, w, h, gl.RGBA, gl.UNSIGNED_BYTE)
+
+	// Shrink by picking the nearest pixel,
+	// reading from the bottom row up as OpenGL does.
+	ch := clipWidth * h / w
+	m := c.frames[c.next]
+	if m == nil || m.Bounds().Dy() != ch {
+		m = image.NewRGBA(image.Rect(0, 0, clipWidth, ch))
+	}
+	for y := 0; y < ch; y++ {
+		sy := h - 1 - y*h/ch
+		for x := 0; x < clipWidth; x++ {
+			sx := x * w / clipWidth
+			i, j := m.PixOffset(x, y), (sy*w+sx)*4
+			copy(m.Pix[i:i+3], pix[j:j+3])
+			m.Pix[i+3] = 0xff
+		}
+	}
+	c.frames[c.next] = m
+	c.next = (c.next + 1) % clipLength
+	if c.n < clipLength {
+		c.n++
+	}
+}
+
+// Save writes the frames recorded to a new GIF in the background.
+func (c *clipRecorder) Save() {
+	if c.n == 0 {
+		c.setStatus("NOTHING TO SHARE")
+		return
+	}
+	// The frames are handed over, oldest first,
+	// so that recording can carry on.
+	frames := make([]*image.RGBA, 0, c.n)
+	for i := 0; i < c.n; i++ {
+		frames = append(frames, c.frames[(c.next-c.n+i+clipLength)%clipLength])
+	}
+	c.frames = [clipLength]*image.RGBA{}
+	c.next, c.n = 0, 0
+	c.setStatus("SAVING CLIP")
+	go func() {
+		name, err := writeClip(frames)
+		if err != nil {
+			log.Print(err)
+			c.setStatus("CLIP FAILED")
+			return
+		}
+		log.Print("saved clip ", name)
+		c.setStatus("CLIP SAVED")
+	}()
+}
+
+func (c *clipRecorder) setStatus(s string) {
+	c.mu.Lock()
+	c.status = s
+	c.mu.Unlock()
+}
+
+// Status returns what became of the last clip saved.
+func (c *clipRecorder) Status() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.status
+}
+
+// writeClip writes frames as an animated GIF and returns the file's name.
+func writeClip(frames []*image.RGBA) (string, error) {
+	anim := &gif.GIF{}
+	for _, m := range frames {
+		p := image.NewPaletted(m.Bounds(), palette.Plan9)
+		draw.Draw(p, p.Bounds(), m, image.ZP, draw.Src)
+		anim.Image = append(anim.Image, p)
+		anim.Delay = append(anim.Delay, clipDelay)
+	}
+	dir := filepath.Join(sim.DataDir(), "clips")
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", err
+	}
+	name := filepath.Join(dir, time.Now().Format("flappy-20060102-150405.gif"))
+	f, err := os.Create(name)
+	if err != nil {
+		return "", err
+	}
+	if err := gif.EncodeAll(f, anim); err != nil {
+		f.Close()
+		return "", err
+	}
+	return name, f.Close()
+}
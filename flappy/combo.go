@@ -0,0 +1,24 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build darwin linux
+
+package flappy
+
+import (
+	"github.com/adg/game/sim"
+	"golang.org/x/mobile/exp/f32"
+	"golang.org/x/mobile/exp/sprite/clock"
+)
+
+// pulseMultiplier scales a so that the multiplier
+// pulses when it has just risen.
+func (g *Game) pulseMultiplier(a *f32.Affine, t clock.Time) {
+	dt := t - g.Combo.Raised
+	if dt < 0 || dt > sim.ComboPulse {
+		return
+	}
+	s := 1 + float32(sim.ComboPulse-dt)/sim.ComboPulse/2
+	a.Scale(a, s, s)
+}
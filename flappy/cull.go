@@ -0,0 +1,44 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build darwin linux
+
+package flappy
+
+import (
+	"github.com/adg/game/sim"
+	"golang.org/x/mobile/exp/f32"
+	"golang.org/x/mobile/exp/sprite"
+)
+
+const cullMargin = sim.TileWidth // how far off screen a node may be and still be drawn, to allow for the camera shake
+
+// A visibility records whether an arranger's node is shown,
+// so that a node that is unused or off screen is hidden with
+// a single call to the engine, and then costs nothing until
+// it is shown again.
+type visibility struct {
+	shown bool
+}
+
+// show shows n with texture x and transform a.
+func (v *visibility) show(eng sprite.Engine, n *sprite.Node, x sprite.SubTex, a f32.Affine) {
+	eng.SetSubTex(n, x)
+	eng.SetTransform(n, a)
+	v.shown = true
+}
+
+// hide hides n, unless it is hidden already.
+func (v *visibility) hide(eng sprite.Engine, n *sprite.Node) {
+	if v.shown {
+		eng.SetSubTex(n, sprite.SubTex{})
+		v.shown = false
+	}
+}
+
+// offScreen reports whether the span of the screen from x0 to x1,
+// in world units, can't be seen.
+func (l layout) offScreen(x0, x1 float32) bool {
+	return x1 < -cullMargin || x0 > l.w+cullMargin
+}
@@ -0,0 +1,34 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build darwin linux
+
+package flappy
+
+import (
+	"math"
+
+	"github.com/adg/game/sim"
+	"golang.org/x/mobile/exp/f32"
+	"golang.org/x/mobile/exp/sprite/clock"
+)
+
+// animateDeadGopher squashes, then tumbles, the gopher
+// drawn with a, time t after it died.
+func animateDeadGopher(a *f32.Affine, t clock.Time) {
+	if t < sim.DeathSquash {
+		// Squash against the bottom of the gopher,
+		// springing back as the squash wears off.
+		s := sim.SquashAmount * (1 - float32(t)/sim.DeathSquash)
+		a.Translate(a, 0.5, 1)
+		a.Scale(a, 1+s, 1-s)
+		a.Translate(a, -0.5, -1)
+		return
+	}
+	dt := float32(t - sim.DeathSquash)
+	a.Scale(a, 1+dt/20, 1+dt/20)
+	a.Translate(a, 0.5, 0.5)
+	a.Rotate(a, dt/math.Pi/-8)
+	a.Translate(a, -0.5, -0.5)
+}
@@ -0,0 +1,176 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build darwin linux
+
+package flappy
+
+import (
+	"fmt"
+	"math"
+	"runtime"
+	"time"
+
+	"github.com/adg/game/sim"
+	"golang.org/x/mobile/exp/f32"
+	"golang.org/x/mobile/exp/sprite"
+	"golang.org/x/mobile/exp/sprite/clock"
+)
+
+const (
+	debugSample   = time.Second // how often the slower statistics are gathered
+	debugLines    = 6           // lines in the debug overlay
+	debugZoomStep = 1.25        // how much each step of the mouse wheel zooms
+	debugMinZoom  = 0.25        // furthest the debug camera zooms out
+	debugMaxZoom  = 4           // furthest the debug camera zooms in
+)
+
+// debugStats are the statistics shown in the debug overlay,
+// for tuning the game's performance on devices.
+type debugStats struct {
+	on        bool
+	last      time.Time     // when the scene was last drawn
+	frameTime time.Duration // time between the last two draws
+	frames    int           // draws since the last sample
+	sampled   time.Time     // when the slower statistics were last gathered
+	fps       float64       // draws per second
+	nodes     int           // nodes in the scene
+	drawn     int           // nodes in the scene with a texture
+	mallocs   uint64        // heap allocations since the game started
+	allocRate float64       // heap allocations per second
+	heap      uint64        // bytes of live heap
+	zoom      float32       // how far the debug camera is zoomed in, or 0 if it isn't
+}
+
+// ToggleDebug shows or hides the debug overlay.
+func (g *Game) ToggleDebug() {
+	g.debug = debugStats{on: !g.debug.on}
+}
+
+// ZoomDebug zooms the debug camera in by steps, or out if steps is negative,
+// so that what lies beyond the edges of the screen may be seen.
+// It does nothing unless the debug overlay is shown.
+func (g *Game) ZoomDebug(steps int) {
+	if !g.debug.on {
+		return
+	}
+	z := g.debug.zoom
+	if z == 0 {
+		z = 1
+	}
+	z *= float32(math.Pow(debugZoomStep, float64(steps)))
+	if z < debugMinZoom {
+		z = debugMinZoom
+	}
+	if z > debugMaxZoom {
+		z = debugMaxZoom
+	}
+	g.debug.zoom = z
+}
+
+// debugCamera returns the scene transform a zoomed by the debug camera,
+// about the middle of the world shown.
+func (g *Game) debugCamera(a f32.Affine) f32.Affine {
+	z := g.debug.zoom
+	if z == 0 {
+		return a
+	}
+	l := g.view
+	cx, cy := l.x+l.w*l.scale/2, l.y+sim.WorldH*l.scale/2
+	return f32.Affine{
+		{a[0][0] * z, a[0][1] * z, cx + (a[0][2]-cx)*z},
+		{a[1][0] * z, a[1][1] * z, cy + (a[1][2]-cy)*z},
+	}
+}
+
+// measure notes that the scene rooted at root is being drawn.
+func (d *debugStats) measure(root *sprite.Node) {
+	now := time.Now()
+	if !d.last.IsZero() {
+		d.frameTime = now.Sub(d.last)
+	}
+	d.last = now
+	d.frames++
+	if d.sampled.IsZero() {
+		d.sampled = now
+		return
+	}
+	dt := now.Sub(d.sampled)
+	if dt < debugSample {
+		return
+	}
+	d.fps = float64(d.frames) / dt.Seconds()
+	d.frames = 0
+	d.sampled = now
+	d.nodes, d.drawn = countNodes(root)
+
+	// Reading the memory statistics stops the world,
+	// so they are only read once a sample.
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+	if d.mallocs != 0 {
+		d.allocRate = float64(m.Mallocs-d.mallocs) / dt.Seconds()
+	}
+	d.mallocs = m.Mallocs
+	d.heap = m.HeapAlloc
+}
+
+// countNodes returns the number of nodes in the tree rooted at n,
+// and how many of them have a texture.
+func countNodes(n *sprite.Node) (nodes, drawn int) {
+	nodes = 1
+	if n.EngineFields.SubTex.T != nil {
+		drawn = 1
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		cn, cd := countNodes(c)
+		nodes += cn
+		drawn += cd
+	}
+	return nodes, drawn
+}
+
+// debugText returns line i of the debug overlay.
+func (g *Game) debugText(i int) string {
+	d := &g.debug
+	if !d.on {
+		return ""
+	}
+	switch i {
+	case 0:
+		return fmt.Sprintf("FPS %.0f", d.fps)
+	case 1:
+		return fmt.Sprintf("FRAME %.1fMS", float64(d.frameTime)/float64(time.Millisecond))
+	case 2:
+		return fmt.Sprintf("SCROLL V %.1f", g.Scroll.V)
+	case 3:
+		return fmt.Sprintf("GOPHER V %.1f", g.Gopher.V)
+	case 4:
+		return fmt.Sprintf("NODES %d/%d", d.drawn, d.nodes)
+	case 5:
+		return fmt.Sprintf("ALLOCS %.0f/S HEAP %dK", d.allocRate, d.heap/1024)
+	}
+	return ""
+}
+
+// addDebug adds the debug overlay to parent,
+// the HUD of the scene rooted at root.
+func (g *Game) addDebug(eng sprite.Engine, root, parent *sprite.Node, f *font) {
+	n := &sprite.Node{Arranger: arrangerFunc(func(eng sprite.Engine, n *sprite.Node, t clock.Time) {
+		if g.debug.on {
+			g.debug.measure(root)
+		}
+	})}
+	eng.Register(n)
+	parent.AppendChild(n)
+	for i := 0; i < debugLines; i++ {
+		i := i
+		newText(eng, parent, f, f32.Affine{
+			{glyphWidth, 0, sim.TileWidth / 2},
+			{0, glyphHeight, sim.TileHeight*3 + glyphHeight*float32(i)*5/4},
+		}, 24, alignLeft, func() string {
+			return g.debugText(i)
+		})
+	}
+}
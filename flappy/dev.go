@@ -0,0 +1,83 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build darwin linux
+// +build dev
+
+package flappy
+
+import (
+	"log"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+)
+
+// Development builds, made with the dev build tag, watch the asset directory
+// and reload the assets whenever they change, so that the art and settings
+// can be tweaked while the game runs, without redeploying it.
+
+const watchEvery = time.Second // how often to look for changed assets
+
+// assetDirEnv names the environment variable that gives the asset directory
+// to watch, if it isn't "assets" in the working directory.
+const assetDirEnv = "FLAPPY_ASSETS"
+
+var assetsStale int32 // set to 1 when the assets have changed
+
+func init() {
+	go watchAssets()
+}
+
+// watchAssets polls the modification times of the files
+// in the asset directory, marking the assets stale when they change.
+func watchAssets() {
+	dir := os.Getenv(assetDirEnv)
+	if dir == "" {
+		dir = "assets"
+	}
+	log.Printf("dev: watching %s for changes", dir)
+	last := modTimes(dir)
+	for range time.Tick(watchEvery) {
+		now := modTimes(dir)
+		if changed(last, now) {
+			atomic.StoreInt32(&assetsStale, 1)
+		}
+		last = now
+	}
+}
+
+// modTimes returns the modification time of each file in dir.
+func modTimes(dir string) map[string]time.Time {
+	m := make(map[string]time.Time)
+	files, err := filepath.Glob(filepath.Join(dir, "*"))
+	if err != nil {
+		log.Print(err)
+		return m
+	}
+	for _, name := range files {
+		if fi, err := os.Stat(name); err == nil {
+			m[name] = fi.ModTime()
+		}
+	}
+	return m
+}
+
+func changed(a, b map[string]time.Time) bool {
+	if len(a) != len(b) {
+		return true
+	}
+	for name, t := range a {
+		if !b[name].Equal(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// assetsChanged reports whether the assets have changed since it was last called.
+func assetsChanged() bool {
+	return atomic.SwapInt32(&assetsStale, 0) == 1
+}
@@ -0,0 +1,65 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build darwin linux
+
+package flappy
+
+import (
+	"github.com/adg/game/sim"
+	"golang.org/x/mobile/exp/f32"
+	"golang.org/x/mobile/exp/sprite"
+	"golang.org/x/mobile/exp/sprite/clock"
+)
+
+// addEntities adds a node to parent that draws the entities in the given layer.
+func (g *Game) addEntities(eng sprite.Engine, parent *sprite.Node, texs []sprite.SubTex, layer int) {
+	var parts [sim.MaxEntities]*sprite.Node
+	var vis [sim.MaxEntities]visibility
+	entities := &sprite.Node{Arranger: arrangerFunc(func(eng sprite.Engine, _ *sprite.Node, t clock.Time) {
+		for i := range vis {
+			if i >= len(g.Entities) {
+				vis[i].hide(eng, parts[i])
+				continue
+			}
+			e := &g.Entities[i]
+			x := e.X - g.drawScroll()
+			if !e.Drawn() || e.Layer != layer || g.view.offScreen(x, x+e.W) {
+				vis[i].hide(eng, parts[i])
+				continue
+			}
+			y, w, h := e.Y, e.W, e.H
+			if s := g.Scale(e); s != 1 {
+				// Shrink about the middle.
+				x, y = x+w*(1-s)/2, y+h*(1-s)/2
+				w, h = w*s, h*s
+			}
+			if e.Hang {
+				// Pipes hang from the top of the screen, however tall it is.
+				y, h = g.view.top(), h+y-g.view.top()
+			}
+			a := f32.Affine{
+				{w, 0, x},
+				{0, h, y},
+			}
+			switch {
+			case e.Flipped, e.Tumble && sim.Frame(t, 6, 0, 1) == 1:
+				a[1][1] = -h
+				a[1][2] += h
+			case e.Flap && sim.Frame(t, sim.EnemyFlap, 0, 1) == 1:
+				// Squash the wings down.
+				a[1][1] = h / 2
+				a[1][2] += h / 4
+			}
+			vis[i].show(eng, parts[i], texs[e.Tex], a)
+		}
+	})}
+	eng.Register(entities)
+	parent.AppendChild(entities)
+	for i := range parts {
+		parts[i] = &sprite.Node{}
+		eng.Register(parts[i])
+		entities.AppendChild(parts[i])
+	}
+}
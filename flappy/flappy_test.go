@@ -0,0 +1,68 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build darwin linux
+
+package flappy_test
+
+import (
+	"image"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/adg/game/flappy"
+	"github.com/adg/game/sim"
+	"golang.org/x/mobile/event/size"
+	"golang.org/x/mobile/exp/sprite/clock"
+	"golang.org/x/mobile/exp/sprite/portable"
+)
+
+func TestMain(m *testing.M) {
+	// The assets are beside the command that bundles them,
+	// and the tests' saves are kept out of the user's own.
+	if err := os.Chdir("../cmd/flappy"); err != nil {
+		panic(err)
+	}
+	dir, err := ioutil.TempDir("", "flappy")
+	if err != nil {
+		panic(err)
+	}
+	os.Setenv("HOME", dir)
+	os.Setenv("XDG_CONFIG_HOME", dir)
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+// TestGame plays a run, drawing each frame on a software sprite engine.
+func TestGame(t *testing.T) {
+	sz := size.Event{WidthPx: 480, HeightPx: 320, WidthPt: 480, HeightPt: 320, PixelsPerPt: 1}
+	eng := portable.Engine(image.NewRGBA(sz.Bounds()))
+	defer eng.Release()
+
+	g := flappy.NewGame(sim.Endless, sim.WithSeed(1))
+	scene := g.Scene(eng)
+	g.Resize(sz)
+	g.Press(true)
+	g.Press(false)
+	var now clock.Time
+	for end := clock.Time(sim.ClockRate * 60 * 10); !g.Over(); now++ {
+		if now == end {
+			t.Fatalf("run didn't end, in state %v", g.State())
+		}
+		switch now % 45 {
+		case 0:
+			g.Press(true)
+		case 12:
+			g.Press(false)
+		}
+		g.Update(now)
+		g.Interpolate(0.5)
+		eng.Render(scene, now, sz)
+	}
+	if g.Travelled == 0 {
+		t.Error("gopher didn't run")
+	}
+}
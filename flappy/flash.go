@@ -0,0 +1,46 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build darwin linux
+
+package flappy
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/adg/game/sim"
+	"golang.org/x/mobile/exp/sprite"
+	"golang.org/x/mobile/exp/sprite/clock"
+)
+
+// flashTex returns the flash to draw over the screen at t, if any.
+func (g *Game) flashTex(texs []sprite.SubTex, t clock.Time) (sprite.SubTex, bool) {
+	dt := t - g.Gopher.DeadTime
+	if !g.Over() || g.Finished() || dt < 0 || dt >= sim.FlashTime {
+		return sprite.SubTex{}, false
+	}
+	if dt < sim.FlashWhite {
+		return skyTex(texs[sim.TexFlashWhite], sim.FlashAlpha), true
+	}
+	f := 1 - float32(dt-sim.FlashWhite)/(sim.FlashTime-sim.FlashWhite)
+	return skyTex(texs[sim.TexFlashRed], sim.FlashAlpha*f), true
+}
+
+// paintGradient returns a painter that paints colour c
+// from transparent on the left to opaque on the right.
+func paintGradient(c color.RGBA) func(m *image.RGBA, r image.Rectangle) {
+	return func(m *image.RGBA, r image.Rectangle) {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			a := uint32(0xff * (x - r.Min.X) / (r.Dx() - 1))
+			// Premultiply, as the sprite engine expects.
+			fillRect(m, image.Rect(x, r.Min.Y, x+1, r.Max.Y), color.RGBA{
+				uint8(uint32(c.R) * a / 0xff),
+				uint8(uint32(c.G) * a / 0xff),
+				uint8(uint32(c.B) * a / 0xff),
+				uint8(a),
+			})
+		}
+	}
+}
@@ -0,0 +1,743 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build darwin linux
+
+// Package flappy is the Flappy Gopher app: it draws a sim.Game on a sprite
+// engine and plays it with touches, keys, and gamepads. Apps embed the game
+// by running Main, or by drawing a Game's Scene themselves.
+package flappy
+
+import (
+	"image"
+	"image/draw"
+	_ "image/png"
+	"io"
+	"log"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/adg/game/sim"
+	"golang.org/x/mobile/asset"
+	"golang.org/x/mobile/event/size"
+	"golang.org/x/mobile/exp/f32"
+	"golang.org/x/mobile/exp/sprite"
+	"golang.org/x/mobile/exp/sprite/clock"
+)
+
+// A Game draws a sim.Game, which it plays, on a sprite engine.
+type Game struct {
+	*sim.Game
+
+	debug debugStats      // what the debug overlay shows
+	view  layout          // how the world fits on the screen
+	texs  []sprite.SubTex // the textures, once loaded
+	font  *font           // the font, once loaded
+}
+
+// NewGame returns a game played by the rules of the given mode, with its
+// data read from the app's assets, laid out for a screen of no size until
+// it is resized.
+func NewGame(mode sim.Mode, opts ...sim.Option) *Game {
+	opts = append([]sim.Option{sim.WithAssets(openAsset)}, opts...)
+	return &Game{Game: sim.NewGame(mode, opts...), view: newLayout(size.Event{})}
+}
+
+// openAsset opens the asset called name.
+func openAsset(name string) (io.ReadCloser, error) {
+	return asset.Open(name)
+}
+
+// assets returns the textures and font, loading them the first time.
+func (g *Game) assets(eng sprite.Engine) ([]sprite.SubTex, *font) {
+	if g.texs == nil {
+		g.texs = loadTextures(eng, g.Style())
+		g.font = loadFont(eng)
+		g.OnRestyle(func() { g.reloadTextures(eng) })
+	}
+	return g.texs, g.font
+}
+
+// ReloadAssets loads the textures, difficulty presets, and physics again.
+// The textures are replaced in place, so that the scenes show them
+// straight away, and the old ones are released.
+func (g *Game) ReloadAssets(eng sprite.Engine) {
+	g.ReloadData()
+	g.reloadTextures(eng)
+}
+
+// reloadTextures replaces the textures in place,
+// if they have been loaded, and releases the old ones.
+func (g *Game) reloadTextures(eng sprite.Engine) {
+	if g.texs == nil {
+		return
+	}
+	old := make(map[sprite.Texture]bool)
+	for _, x := range g.texs {
+		if x.T != nil {
+			old[x.T] = true
+		}
+	}
+	copy(g.texs, loadTextures(eng, g.Style()))
+	for t := range old {
+		t.Release()
+	}
+}
+
+func (g *Game) Scene(eng sprite.Engine) *sprite.Node {
+	texs, font := g.assets(eng)
+
+	// The scene is fitted to the screen, offset by the camera shake,
+	// and zoomed by the debug camera.
+	scene := &sprite.Node{Arranger: arrangerFunc(func(eng sprite.Engine, n *sprite.Node, t clock.Time) {
+		eng.SetTransform(n, g.debugCamera(g.view.transform(g.ShakeOffset(g.LastCalc))))
+	})}
+	eng.Register(scene)
+	eng.SetTransform(scene, g.view.transform(0, 0))
+
+	parent := scene
+	newNode := func(fn arrangerFunc) {
+		n := &sprite.Node{Arranger: arrangerFunc(fn)}
+		eng.Register(n)
+		parent.AppendChild(n)
+	}
+
+	// The sky.
+	newNode(func(eng sprite.Engine, n *sprite.Node, t clock.Time) {
+		eng.SetSubTex(n, skyTex(texs[sim.TexSky], g.Day))
+		eng.SetTransform(n, screenCover)
+	})
+
+	// The distant scenery, drifting by.
+	g.addLayer(newNode, texs, clouds)
+	g.addLayer(newNode, texs, hills)
+
+	// The ground.
+	g.addGround(eng, parent, texs)
+
+	// The platforms.
+	g.addEntities(eng, parent, texs, sim.LayerPlatforms)
+
+	// The balloons marking the ends of the low gravity zones.
+	for i := 0; i < sim.MaxZones*2; i++ {
+		i := i
+		var vis visibility
+		newNode(func(eng sprite.Engine, n *sprite.Node, t clock.Time) {
+			if i/2 >= len(g.Zones) {
+				vis.hide(eng, n)
+				return
+			}
+			z := &g.Zones[i/2]
+			x := z.X0
+			if i%2 == 1 {
+				x = z.X1 - sim.BalloonSize
+			}
+			x -= g.drawScroll()
+			if g.view.offScreen(x, x+sim.BalloonSize) {
+				vis.hide(eng, n)
+				return
+			}
+			a := 2 * math.Pi * float64(t%sim.BalloonSway) / sim.BalloonSway
+			vis.show(eng, n, texs[sim.TexBalloon], f32.Affine{
+				{sim.BalloonSize, 0, x},
+				{0, sim.BalloonSize, z.Y + sim.BalloonBob*float32(math.Sin(a+float64(i)))},
+			})
+		})
+	}
+
+	// The obstacles.
+	g.addEntities(eng, parent, texs, sim.LayerObstacles)
+
+	// The coins and pickups.
+	g.addEntities(eng, parent, texs, sim.LayerItems)
+
+	// The enemies, beating their wings.
+	g.addEntities(eng, parent, texs, sim.LayerEnemies)
+
+	// The boss.
+	newNode(func(eng sprite.Engine, n *sprite.Node, t clock.Time) {
+		if !g.BossActive() {
+			eng.SetSubTex(n, sprite.SubTex{})
+			return
+		}
+		eng.SetSubTex(n, texs[sim.TexMole])
+		eng.SetTransform(n, f32.Affine{
+			{sim.BossSize, 0, g.BossX()},
+			{0, sim.BossSize, g.BossY()},
+		})
+	})
+
+	// The magnet's aura around the gopher.
+	newNode(func(eng sprite.Engine, n *sprite.Node, t clock.Time) {
+		if !g.Magnetised || g.Over() {
+			eng.SetSubTex(n, sprite.SubTex{})
+			return
+		}
+		// Pulse gently.
+		s := sim.TileWidth*3 + sim.TileWidth/2*float32(math.Sin(float64(t)/8))
+		x0, y0, x1, y1 := g.GopherBounds()
+		eng.SetSubTex(n, texs[sim.TexAura])
+		eng.SetTransform(n, f32.Affine{
+			{s, 0, (x0+x1-s)/2 - g.drawScroll()},
+			{0, s, (y0 + y1 - s) / 2},
+		})
+	})
+
+	// The gopher's shield, or its pieces flying apart.
+	newNode(func(eng sprite.Engine, n *sprite.Node, t clock.Time) {
+		if !g.Gopher.Shielded || g.Over() {
+			eng.SetSubTex(n, sprite.SubTex{})
+			return
+		}
+		const s = sim.TileWidth * 3
+		eng.SetSubTex(n, texs[sim.TexRing])
+		eng.SetTransform(n, f32.Affine{
+			{s, 0, g.drawGopherX() + (sim.TileWidth-s)/2},
+			{0, s, g.drawGopherY() + (sim.TileHeight-s)/2},
+		})
+	})
+	for i := 0; i < sim.Shards; i++ {
+		angle := 2 * math.Pi * float64(i) / sim.Shards
+		newNode(func(eng sprite.Engine, n *sprite.Node, t clock.Time) {
+			dt := g.LastCalc - g.Gopher.Shattered
+			if dt >= sim.ShatterTime {
+				eng.SetSubTex(n, sprite.SubTex{})
+				return
+			}
+			const s = sim.TileWidth / 2
+			d := float32(dt)/sim.ClockRate*sim.ShardV + sim.TileWidth
+			eng.SetSubTex(n, texs[sim.TexShard])
+			eng.SetTransform(n, f32.Affine{
+				{s, 0, g.drawGopherX() + (sim.TileWidth-s)/2 + d*float32(math.Cos(angle))},
+				{0, s, g.drawGopherY() + (sim.TileHeight-s)/2 + d*float32(math.Sin(angle))},
+			})
+		})
+	}
+
+	// The trail left behind the gopher when it runs fast.
+	var gopherTrail trail
+	g.addTrail(eng, parent, texs, &gopherTrail)
+
+	// The gopher.
+	var gopherAnim animator
+	newNode(func(eng sprite.Engine, n *sprite.Node, t clock.Time) {
+		a := f32.Affine{
+			{sim.TileWidth * 2, 0, g.drawGopherX() - sim.TileWidth + sim.TileWidth/8},
+			{0, sim.TileHeight * 2, g.drawGopherY() - sim.TileHeight + sim.TileHeight/4},
+		}
+		var anim *animation
+		switch {
+		case g.Finished():
+			anim = animFinish
+		case g.Over() && t-g.Gopher.DeadTime < sim.DeathSquash:
+			anim = animSquash
+			animateDeadGopher(&a, t-g.Gopher.DeadTime)
+		case g.Over():
+			anim = animDeath
+			animateDeadGopher(&a, t-g.Gopher.DeadTime)
+		case g.Gopher.Grab == sim.GrabHanging:
+			anim = animHang
+		case g.Gopher.Grab == sim.GrabClimbing:
+			anim = animClimb
+		case g.Gopher.Sliding:
+			// Sliding gophers are half as tall.
+			anim = animSlide
+			a[1][1] = sim.TileHeight
+			a[1][2] = g.drawGopherY() + sim.TileHeight/4
+		case g.InWater():
+			// Swimming gophers lean into the water.
+			anim = animSwim
+			a[0][1] = -sim.TileWidth / 2
+			a[0][2] += sim.TileWidth / 2
+		case g.Gopher.Gliding:
+			anim = animGlide
+		case g.Gopher.V < 0:
+			anim = animFlap
+		case g.Gopher.AtRest:
+			anim = animRun
+		default:
+			anim = animFall
+		}
+		if !g.Over() {
+			g.stretchGopherAffine(&a, t)
+		}
+		x := gopherAnim.tex(anim, t)
+		gopherTrail.record(t, a, x, g.Travelled)
+		if g.Invulnerable() && sim.Frame(t, 4, 0, 1) == 1 {
+			// Flicker while invulnerable.
+			eng.SetSubTex(n, sprite.SubTex{})
+			return
+		}
+		if g.Gopher.Starred && !g.Over() {
+			// Flash the colours of the rainbow.
+			x = starTex(x, int(t))
+		} else {
+			x = g.gopherTex(x)
+		}
+		eng.SetSubTex(n, texs[x])
+		eng.SetTransform(n, a)
+	})
+
+	// The water in the pools, in front of the gopher.
+	g.addWater(eng, parent, texs)
+
+	// The acorns, tumbling as they fly.
+	g.addEntities(eng, parent, texs, sim.LayerAcorns)
+
+	// The hat the gopher has earned.
+	newNode(func(eng sprite.Engine, n *sprite.Node, t clock.Time) {
+		h, ok := g.Hat()
+		if !ok || g.Over() || g.Invulnerable() && sim.Frame(t, 4, 0, 1) == 1 {
+			eng.SetSubTex(n, sprite.SubTex{})
+			return
+		}
+		y := g.drawGopherY() - sim.TileHeight/2
+		if g.Gopher.Sliding {
+			y += sim.TileHeight / 2
+		}
+		eng.SetSubTex(n, texs[h.Tex])
+		eng.SetTransform(n, f32.Affine{
+			{sim.TileWidth * 3 / 4, 0, g.drawGopherX() + sim.TileWidth/8},
+			{0, sim.TileHeight * 3 / 4, y},
+		})
+	})
+
+	// The leaves, tumbling in the wind.
+	for i := 0; i < sim.MaxLeaves; i++ {
+		i := i
+		var vis visibility
+		newNode(func(eng sprite.Engine, n *sprite.Node, t clock.Time) {
+			if i >= len(g.Leaves) {
+				vis.hide(eng, n)
+				return
+			}
+			l := &g.Leaves[i]
+			a := f32.Affine{
+				{sim.LeafSize, 0, l.X},
+				{0, sim.LeafSize, l.Y},
+			}
+			if sim.Frame(t, 8, 0, 1) == 1 {
+				// Flip to tumble.
+				a[0][0] = -sim.LeafSize
+				a[0][2] += sim.LeafSize
+			}
+			vis.show(eng, n, texs[sim.TexLeaf], a)
+		})
+	}
+
+	// The particles.
+	g.addEntities(eng, parent, texs, sim.LayerParticles)
+
+	// The grass in the foreground, rushing by.
+	g.addLayer(newNode, texs, grass)
+
+	// The time attack split markers.
+	for _, d := range sim.SplitMarkers {
+		d := d
+		newNode(func(eng sprite.Engine, n *sprite.Node, t clock.Time) {
+			x, ok := g.MarkerX(d)
+			if g.Mode != sim.TimeAttack || !ok {
+				eng.SetSubTex(n, sprite.SubTex{})
+				return
+			}
+			eng.SetSubTex(n, texs[sim.TexFlag])
+			eng.SetTransform(n, f32.Affine{
+				{sim.TileWidth, 0, x},
+				{0, sim.TileHeight * 2, g.GroundAt(x+g.drawScroll()) - sim.TileHeight*2},
+			})
+		})
+	}
+
+	// The darkness of night and caves.
+	g.addLighting(eng, parent, texs)
+
+	// The flash when the gopher dies.
+	newNode(func(eng sprite.Engine, n *sprite.Node, t clock.Time) {
+		x, ok := g.flashTex(texs, t)
+		if !ok {
+			eng.SetSubTex(n, sprite.SubTex{})
+			return
+		}
+		eng.SetSubTex(n, x)
+		eng.SetTransform(n, screenCover)
+	})
+
+	// The HUD, kept at the top of the screen however tall it is.
+	hud := &sprite.Node{Arranger: arrangerFunc(func(eng sprite.Engine, n *sprite.Node, t clock.Time) {
+		eng.SetTransform(n, f32.Affine{
+			{1, 0, g.view.hudX()},
+			{0, 1, g.view.top()},
+		})
+	})}
+	eng.Register(hud)
+	scene.AppendChild(hud)
+	parent = hud
+
+	// The score.
+	newText(eng, hud, font, f32.Affine{
+		{glyphWidth, 0, sim.TileWidth / 2},
+		{0, glyphHeight, sim.TileHeight / 2},
+	}, sim.ScoreDigits, alignLeft, func() string {
+		return g.ScoreText()
+	})
+
+	// The score multiplier, which pulses when it rises.
+	mult := &sprite.Node{Arranger: arrangerFunc(func(eng sprite.Engine, n *sprite.Node, t clock.Time) {
+		a := f32.Affine{
+			{1, 0, sim.TileWidth/2 + glyphWidth*(sim.ScoreDigits+2)},
+			{0, 1, sim.TileHeight/2 + glyphHeight/2},
+		}
+		g.pulseMultiplier(&a, t)
+		eng.SetTransform(n, a)
+	})}
+	eng.Register(mult)
+	hud.AppendChild(mult)
+	newText(eng, mult, font, f32.Affine{
+		{glyphWidth, 0, 0},
+		{0, glyphHeight, -glyphHeight / 2},
+	}, 2, alignCenter, func() string {
+		m := g.Multiplier()
+		if m == 1 || g.Over() {
+			return ""
+		}
+		return "X" + strconv.Itoa(m)
+	})
+
+	// The sign heralding a gust of wind.
+	newText(eng, hud, font, f32.Affine{
+		{glyphWidth, 0, sim.TileWidth * sim.TilesX / 2},
+		{0, glyphHeight, sim.TileHeight * 5 / 2},
+	}, 11, alignCenter, g.WindText)
+
+	// The latest time attack split, against the fastest.
+	newText(eng, hud, font, f32.Affine{
+		{glyphWidth, 0, sim.TileWidth * sim.TilesX / 2},
+		{0, glyphHeight, sim.TileHeight / 2},
+	}, 8, alignCenter, g.SplitText)
+
+	// The coins collected this run.
+	newNode(func(eng sprite.Engine, n *sprite.Node, t clock.Time) {
+		eng.SetSubTex(n, texs[sim.TexCoin])
+		eng.SetTransform(n, f32.Affine{
+			{glyphHeight, 0, sim.TileWidth / 2},
+			{0, glyphHeight, sim.TileHeight/2 + glyphHeight*3/2},
+		})
+	})
+	newText(eng, hud, font, f32.Affine{
+		{glyphWidth, 0, sim.TileWidth/2 + glyphHeight*3/2},
+		{0, glyphHeight, sim.TileHeight/2 + glyphHeight*3/2},
+	}, sim.ScoreDigits, alignLeft, func() string {
+		return strconv.Itoa(g.Collected)
+	})
+
+	// The dash, shaded while it recharges.
+	newNode(func(eng sprite.Engine, n *sprite.Node, t clock.Time) {
+		eng.SetSubTex(n, texs[sim.TexDash])
+		eng.SetTransform(n, f32.Affine{
+			{sim.TileWidth, 0, sim.TileWidth*sim.TilesX - sim.TileWidth*3/2},
+			{0, sim.TileHeight, sim.TileHeight * 2},
+		})
+	})
+	newNode(func(eng sprite.Engine, n *sprite.Node, t clock.Time) {
+		c := g.DashCharge()
+		if c == 1 {
+			eng.SetSubTex(n, sprite.SubTex{})
+			return
+		}
+		eng.SetSubTex(n, texs[sim.TexShade])
+		eng.SetTransform(n, f32.Affine{
+			{sim.TileWidth, 0, sim.TileWidth*sim.TilesX - sim.TileWidth*3/2},
+			{0, sim.TileHeight * (1 - c), sim.TileHeight * 2},
+		})
+	})
+
+	// The remaining lives, not counting the current one.
+	for i := 0; i < sim.InitLives-1; i++ {
+		i := i
+		newNode(func(eng sprite.Engine, n *sprite.Node, t clock.Time) {
+			if i >= g.Gopher.Lives-1 {
+				eng.SetSubTex(n, sprite.SubTex{})
+				return
+			}
+			eng.SetSubTex(n, texs[sim.TexGopherRun1])
+			eng.SetTransform(n, f32.Affine{
+				{sim.TileWidth, 0, sim.TileWidth/2 + float32(i)*sim.TileWidth},
+				{0, sim.TileHeight, sim.TileHeight/2 + glyphHeight*3},
+			})
+		})
+	}
+
+	// The latest active power-up, which blinks as it is about to expire.
+	newNode(func(eng sprite.Engine, n *sprite.Node, t clock.Time) {
+		a, ok := g.LatestPowerUp()
+		if !ok || a.Until-g.LastCalc < sim.ExpiryWarned && sim.Frame(t, 8, 0, 1) == 1 {
+			eng.SetSubTex(n, sprite.SubTex{})
+			return
+		}
+		eng.SetSubTex(n, texs[a.P.Tex()])
+		eng.SetTransform(n, f32.Affine{
+			{sim.TileWidth, 0, sim.TileWidth*sim.TilesX - sim.TileWidth*3/2},
+			{0, sim.TileHeight, sim.TileHeight / 2},
+		})
+	})
+
+	// The character select screen, or the shop.
+	newText(eng, hud, font, f32.Affine{
+		{glyphWidth, 0, sim.TileWidth * sim.TilesX / 2},
+		{0, glyphHeight, sim.TileHeight*3 - glyphHeight*2},
+	}, 32, alignCenter, func() string {
+		switch {
+		case g.Shopping():
+			return "SHOP  " + strconv.Itoa(g.Coins()) + " COINS"
+		case g.Choosing() && g.Daily():
+			return strings.ToUpper(g.Mode.String()) + " DAILY  " + strconv.Itoa(g.Coins()) + " COINS"
+		case g.Choosing():
+			return strings.ToUpper(g.Mode.String()) + "  " + strconv.Itoa(g.Coins()) + " COINS"
+		}
+		return ""
+	})
+	newText(eng, hud, font, f32.Affine{
+		{glyphWidth, 0, sim.TileWidth * sim.TilesX / 2},
+		{0, glyphHeight, sim.TileHeight * 3},
+	}, 24, alignCenter, func() string {
+		switch {
+		case g.Shopping():
+			return "< " + strings.ToUpper(g.ShopItems()[g.ShopItem].Name) + " >"
+		case g.Choosing():
+			return "< " + strings.ToUpper(sim.Characters[g.Character].Name) + " >"
+		}
+		return ""
+	})
+	newText(eng, hud, font, f32.Affine{
+		{glyphWidth, 0, sim.TileWidth * sim.TilesX / 2},
+		{0, glyphHeight, sim.TileHeight*3 + glyphHeight*3},
+	}, 20, alignCenter, func() string {
+		switch {
+		case g.Shopping():
+			return g.ShopPrompt()
+		case g.Choosing():
+			return g.CharacterPrompt()
+		}
+		return ""
+	})
+
+	// The achievement just unlocked.
+	newText(eng, hud, font, f32.Affine{
+		{glyphWidth, 0, sim.TileWidth * sim.TilesX / 2},
+		{0, glyphHeight, sim.TileHeight * 3},
+	}, 8, alignCenter, func() string {
+		if g.Toast == "" || g.LastCalc-g.ToastTime > sim.ToastTime {
+			return ""
+		}
+		return "UNLOCKED"
+	})
+	newText(eng, hud, font, f32.Affine{
+		{glyphWidth, 0, sim.TileWidth * sim.TilesX / 2},
+		{0, glyphHeight, sim.TileHeight*3 + glyphHeight*3/2},
+	}, 20, alignCenter, func() string {
+		if g.Toast == "" || g.LastCalc-g.ToastTime > sim.ToastTime {
+			return ""
+		}
+		return strings.ToUpper(g.Toast)
+	})
+
+	// The level the player has just reached.
+	newText(eng, hud, font, f32.Affine{
+		{glyphWidth * 2, 0, sim.TileWidth * sim.TilesX / 2},
+		{0, glyphHeight * 2, sim.TileHeight*sim.TilesY/3 - glyphHeight*3},
+	}, 9, alignCenter, g.LevelBanner)
+
+	// The best score, shown when the gopher dies.
+	newText(eng, hud, font, f32.Affine{
+		{glyphWidth * 2, 0, sim.TileWidth * sim.TilesX / 2},
+		{0, glyphHeight * 2, sim.TileHeight * sim.TilesY / 3},
+	}, sim.ScoreDigits+5, alignCenter, func() string {
+		if !g.Over() || g.GameOver() {
+			return ""
+		}
+		return g.BestText()
+	})
+
+	// The time left to survive the boss.
+	newText(eng, hud, font, f32.Affine{
+		{glyphWidth, 0, sim.TileWidth * sim.TilesX / 2},
+		{0, glyphHeight, sim.TileHeight / 2},
+	}, 8, alignCenter, func() string {
+		s := g.BossTimeLeft()
+		if s == 0 {
+			return ""
+		}
+		return "BOSS " + strconv.Itoa(s)
+	})
+
+	// The offer to continue from the last checkpoint.
+	newText(eng, hud, font, f32.Affine{
+		{glyphWidth, 0, sim.TileWidth * sim.TilesX / 2},
+		{0, glyphHeight, sim.TileHeight*sim.TilesY/3 + glyphHeight*3},
+	}, 20, alignCenter, func() string {
+		if !g.CanContinue() || g.GameOver() {
+			return ""
+		}
+		if g.Continues() > 0 {
+			return "CONTINUE " + strconv.Itoa(g.Continues()) + " LEFT"
+		}
+		return "CONTINUE " + strconv.Itoa(sim.ContinueCost) + " COINS"
+	})
+
+	// The dimmed scene behind the pause menu and the summary.
+	newNode(func(eng sprite.Engine, n *sprite.Node, t clock.Time) {
+		if !g.Paused() && !g.GameOver() {
+			eng.SetSubTex(n, sprite.SubTex{})
+			return
+		}
+		eng.SetSubTex(n, skyTex(texs[sim.TexFade], 0.5))
+		eng.SetTransform(n, screenCover)
+	})
+	newText(eng, hud, font, f32.Affine{
+		{glyphWidth * 2, 0, sim.TileWidth * sim.TilesX / 2},
+		{0, glyphHeight * 2, sim.TileHeight * sim.TilesY / 3},
+	}, 6, alignCenter, func() string {
+		if !g.Paused() {
+			return ""
+		}
+		return "PAUSED"
+	})
+	newText(eng, hud, font, f32.Affine{
+		{glyphWidth, 0, sim.TileWidth * sim.TilesX / 2},
+		{0, glyphHeight, sim.TileHeight*sim.TilesY/3 + glyphHeight*3},
+	}, 11, alignCenter, g.PausePrompt)
+	newText(eng, hud, font, f32.Affine{
+		{glyphWidth, 0, sim.TileWidth * sim.TilesX / 2},
+		{0, glyphHeight, sim.TileHeight * 3 / 2},
+	}, 6, alignCenter, func() string {
+		if !g.Replaying() {
+			return ""
+		}
+		return "REPLAY"
+	})
+
+	// The summary of the run just ended.
+	newText(eng, hud, font, f32.Affine{
+		{glyphWidth * 2, 0, sim.TileWidth * sim.TilesX / 2},
+		{0, glyphHeight * 2, sim.TileHeight * 3},
+	}, 9, alignCenter, g.SummaryTitle)
+	for i := 0; i < 3; i++ {
+		i := i
+		newText(eng, hud, font, f32.Affine{
+			{glyphWidth, 0, sim.TileWidth * sim.TilesX / 2},
+			{0, glyphHeight, sim.TileHeight*5 + glyphHeight*float32(i)*3/2},
+		}, 20, alignCenter, func() string {
+			if s := g.SummaryLines(); i < len(s) {
+				return s[i]
+			}
+			return ""
+		})
+	}
+	newText(eng, hud, font, f32.Affine{
+		{glyphWidth, 0, sim.TileWidth * sim.TilesX / 2},
+		{0, glyphHeight, sim.TileHeight*sim.TilesY/3 + glyphHeight*3},
+	}, 14, alignCenter, g.SummaryPrompt)
+	newText(eng, hud, font, f32.Affine{
+		{glyphWidth, 0, sim.TileWidth * sim.TilesX / 2},
+		{0, glyphHeight, sim.TileHeight*sim.TilesY/3 + glyphHeight*9/2},
+	}, 16, alignCenter, g.ClipText)
+
+	// The debug overlay.
+	g.addDebug(eng, scene, hud, font)
+
+	// The fade between scenes.
+	newNode(func(eng sprite.Engine, n *sprite.Node, t clock.Time) {
+		a := g.fadeAlpha(t)
+		if a == 0 {
+			eng.SetSubTex(n, sprite.SubTex{})
+			return
+		}
+		eng.SetSubTex(n, skyTex(texs[sim.TexFade], a))
+		eng.SetTransform(n, screenCover)
+	})
+
+	// The letterbox bars, either side of the world.
+	parent = scene
+	for i := 0; i < 2; i++ {
+		i := i
+		newNode(func(eng sprite.Engine, n *sprite.Node, t clock.Time) {
+			eng.SetSubTex(n, skyTex(texs[sim.TexFade], 1))
+			eng.SetTransform(n, g.view.bars()[i])
+		})
+	}
+
+	return scene
+}
+
+type arrangerFunc func(e sprite.Engine, n *sprite.Node, t clock.Time)
+
+func (a arrangerFunc) Arrange(e sprite.Engine, n *sprite.Node, t clock.Time) { a(e, n, t) }
+
+func loadTextures(eng sprite.Engine, st sim.Style) []sprite.SubTex {
+	a, err := asset.Open("sprite.png")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer a.Close()
+
+	m, _, err := image.Decode(a)
+	if err != nil {
+		log.Fatal(err)
+	}
+	const n = 128
+
+	// The theme recolours the ground and earth, and so every biome's.
+	themed := image.NewRGBA(m.Bounds())
+	draw.Draw(themed, themed.Bounds(), m, m.Bounds().Min, draw.Src)
+	tintRect(themed, image.Rect(n*6, 0, n*11, n), st.Theme.Tint)
+	if st.HighContrast {
+		for i := 6; i < 10; i++ {
+			edgeGround(themed, image.Rect(n*i+1, 0, n*(i+1)-1, n))
+		}
+	}
+	m = themed
+
+	t, err := eng.LoadTexture(m)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	texs := []sprite.SubTex{
+		sim.TexGopherRun1:  sprite.SubTex{t, image.Rect(n*0, 0, n*1, n)},
+		sim.TexGopherRun2:  sprite.SubTex{t, image.Rect(n*1, 0, n*2, n)},
+		sim.TexGopherFlap1: sprite.SubTex{t, image.Rect(n*2, 0, n*3, n)},
+		sim.TexGopherFlap2: sprite.SubTex{t, image.Rect(n*3, 0, n*4, n)},
+		sim.TexGopherDead1: sprite.SubTex{t, image.Rect(n*4, 0, n*5, n)},
+		sim.TexGopherDead2: sprite.SubTex{t, image.Rect(n*5, 0, n*6-1, n)},
+		sim.TexGopherSlide: sprite.SubTex{t, image.Rect(n*1, 0, n*2, n)},
+		sim.TexGopherSwim:  sprite.SubTex{t, image.Rect(n*2, 0, n*3, n)},
+		sim.TexGopherGlide: sprite.SubTex{t, image.Rect(n*3, 0, n*4, n)},
+		sim.TexGround1:     sprite.SubTex{t, image.Rect(n*6+1, 0, n*7-1, n)},
+		sim.TexGround2:     sprite.SubTex{t, image.Rect(n*7+1, 0, n*8-1, n)},
+		sim.TexGround3:     sprite.SubTex{t, image.Rect(n*8+1, 0, n*9-1, n)},
+		sim.TexGround4:     sprite.SubTex{t, image.Rect(n*9+1, 0, n*10-1, n)},
+		sim.TexEarth:       sprite.SubTex{t, image.Rect(n*10+1, 0, n*11-1, n)},
+	}
+
+	texs = append(texs, paintTextures(eng, st)...)
+
+	// Each biome has its own ground, by day and by night,
+	// following the other textures, then come the star's tints,
+	// the trail's ghosts, and the other characters.
+	texs = append(texs, loadGroundSets(eng, m, texs)...)
+	texs = append(texs, loadStarSets(eng, m, texs)...)
+	texs = append(texs, loadTrailSets(eng, m, texs)...)
+	return append(texs, loadCharacterSets(eng, m, texs)...)
+}
+
+// tex returns the texture to use for the ground or earth texture x
+// of ground tile i, in its biome and at the current time of day.
+func (g *Game) tex(i, x int) int {
+	set := g.GroundBiome[i] * 2
+	if g.NightGround && g.IsNight() {
+		set++
+	}
+	return sim.TexCount + set*groundSetSize + x - sim.TexGround1
+}
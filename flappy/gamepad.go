@@ -0,0 +1,26 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build darwin linux
+
+package flappy
+
+// Gamepad buttons, as the player knows them,
+// with what they do unless rebound; see defaultBindings.
+const (
+	padA     = iota // jump and flap, and choose
+	padB            // slide, and go back
+	padX            // throw an acorn
+	padRight        // dash, and show the next option
+	padLeft         // show the previous option
+	padStart        // pause
+)
+
+// A gamepadEvent is a gamepad button being pressed or released.
+// Gamepads are watched on platforms that provide them,
+// and their events are sent to the app's event loop.
+type gamepadEvent struct {
+	button int // see padA and friends
+	down   bool
+}
@@ -0,0 +1,10 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build darwin
+
+package flappy
+
+// watchGamepads does nothing, as gamepads aren't supported on darwin yet.
+func watchGamepads(send func(interface{})) {}
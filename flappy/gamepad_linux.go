@@ -0,0 +1,121 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build linux
+
+package flappy
+
+import (
+	"encoding/binary"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Gamepads are read through the Linux joystick interface,
+// which numbers the buttons and axes of most pads alike.
+
+const (
+	padPollEvery = time.Second // how often to look for gamepads plugged in
+	padDeadZone  = 16384       // how far an axis must be pushed to count
+)
+
+// Joystick event types.
+const (
+	jsButton = 0x01
+	jsAxis   = 0x02
+	jsInit   = 0x80 // the initial state of the pad, rather than a change
+)
+
+// jsEvent is the joystick interface's event, as read from the device.
+type jsEvent struct {
+	Time   uint32
+	Value  int16
+	Type   uint8
+	Number uint8
+}
+
+// jsButtons maps joystick buttons to gamepad buttons.
+var jsButtons = map[uint8]int{
+	0: padA,
+	1: padB,
+	2: padX,
+	5: padRight, // right shoulder
+	7: padStart,
+}
+
+// watchGamepads sends the events of every gamepad to send,
+// picking up pads as they are plugged in.
+func watchGamepads(send func(interface{})) {
+	var mu sync.Mutex
+	open := make(map[string]bool)
+	for ; ; time.Sleep(padPollEvery) {
+		names, _ := filepath.Glob("/dev/input/js*")
+		for _, name := range names {
+			mu.Lock()
+			if open[name] {
+				mu.Unlock()
+				continue
+			}
+			f, err := os.Open(name)
+			if err != nil {
+				// Most likely not ours to read; try again later.
+				mu.Unlock()
+				continue
+			}
+			open[name] = true
+			mu.Unlock()
+			go func(name string, f *os.File) {
+				readGamepad(f, send)
+				f.Close()
+				mu.Lock()
+				delete(open, name)
+				mu.Unlock()
+			}(name, f)
+		}
+	}
+}
+
+// readGamepad sends the events read from r until it is unplugged.
+func readGamepad(r io.Reader, send func(interface{})) {
+	// The axes act as buttons, held while pushed.
+	axes := make(map[uint8]int)
+	for {
+		var e jsEvent
+		if err := binary.Read(r, binary.LittleEndian, &e); err != nil {
+			return
+		}
+		if e.Type&jsInit != 0 {
+			continue
+		}
+		switch e.Type {
+		case jsButton:
+			if b, ok := jsButtons[e.Number]; ok {
+				send(gamepadEvent{button: b, down: e.Value != 0})
+			}
+		case jsAxis:
+			// Axis 0 is the left stick, and 6 the D-pad, across.
+			if e.Number != 0 && e.Number != 6 {
+				break
+			}
+			b := -1
+			switch {
+			case e.Value <= -padDeadZone:
+				b = padLeft
+			case e.Value >= padDeadZone:
+				b = padRight
+			}
+			if old, ok := axes[e.Number]; ok && old != b {
+				send(gamepadEvent{button: old, down: false})
+				delete(axes, e.Number)
+			}
+			if _, ok := axes[e.Number]; b >= 0 && !ok {
+				axes[e.Number] = b
+				send(gamepadEvent{button: b, down: true})
+			}
+		}
+	}
+}
@@ -0,0 +1,127 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build darwin linux
+
+package flappy
+
+import (
+	"time"
+
+	"golang.org/x/mobile/event/size"
+	"golang.org/x/mobile/event/touch"
+)
+
+const (
+	swipeDist = 0.08                  // fraction of the screen height a touch must move to swipe
+	tapWindow = 80 * time.Millisecond // how long a touch has to become a swipe before it is a hold
+)
+
+// Gestures recognized from touches.
+const (
+	gestureTap          = iota // a touch let go of before it moved or was held
+	gestureHold                // a touch held still; it lasts until let go
+	gestureSwipeUp             // a touch moved up; it lasts until let go
+	gestureSwipeDown           // a touch moved down; it lasts until let go
+	gestureSwipeForward        // a touch moved right, the way the gopher runs
+	gestureSwipeBack           // a touch moved left; it lasts until let go
+)
+
+// A gestureEvent is a gesture recognized from the touch seq.
+type gestureEvent struct {
+	seq     touch.Sequence
+	gesture int // see gestureTap and friends
+}
+
+// A touchTrack follows a touch until its gesture is known.
+type touchTrack struct {
+	x, y     float32   // where the touch began, in pixels
+	start    time.Time // when the touch began
+	resolved bool      // has its gesture been recognized?
+}
+
+// A gestureRecognizer classifies touches as taps, holds, and swipes.
+// Each touch is recognized as one gesture only.
+type gestureRecognizer struct {
+	tracks map[touch.Sequence]*touchTrack
+}
+
+// gestures recognizes the touches of the swipe controls.
+var gestures gestureRecognizer
+
+// begin starts following the touch e.
+func (r *gestureRecognizer) begin(e touch.Event, now time.Time) {
+	if r.tracks == nil {
+		r.tracks = make(map[touch.Sequence]*touchTrack)
+	}
+	r.tracks[e.Sequence] = &touchTrack{x: e.X, y: e.Y, start: now}
+}
+
+// move returns the swipe, if any, made by the touch e moving
+// on a screen of size sz.
+func (r *gestureRecognizer) move(e touch.Event, sz size.Event) []gestureEvent {
+	t, ok := r.tracks[e.Sequence]
+	if !ok || t.resolved {
+		return nil
+	}
+	dx, dy := e.X-t.x, e.Y-t.y
+	d := swipeDist * float32(sz.HeightPx)
+	if dx*dx+dy*dy < d*d {
+		return nil
+	}
+	t.resolved = true
+	g := gestureSwipeForward
+	switch {
+	case dy*dy > dx*dx && dy < 0:
+		g = gestureSwipeUp
+	case dy*dy > dx*dx:
+		g = gestureSwipeDown
+	case dx < 0:
+		g = gestureSwipeBack
+	}
+	return []gestureEvent{{e.Sequence, g}}
+}
+
+// end stops following the touch e, and returns a tap
+// if it was let go of before it was recognized as anything else.
+func (r *gestureRecognizer) end(e touch.Event) []gestureEvent {
+	t, ok := r.tracks[e.Sequence]
+	if !ok {
+		return nil
+	}
+	delete(r.tracks, e.Sequence)
+	if t.resolved {
+		return nil
+	}
+	return []gestureEvent{{e.Sequence, gestureTap}}
+}
+
+// poll returns the holds of the touches that have been still for long enough.
+func (r *gestureRecognizer) poll(now time.Time) []gestureEvent {
+	var gs []gestureEvent
+	for seq, t := range r.tracks {
+		if !t.resolved && now.Sub(t.start) >= tapWindow {
+			t.resolved = true
+			gs = append(gs, gestureEvent{seq, gestureHold})
+		}
+	}
+	return gs
+}
+
+// bindGestures does the action bound to each of the gestures gs with the swipe controls.
+// Gestures that last until let go hold down their action until then.
+func bindGestures(gs []gestureEvent) {
+	for _, ge := range gs {
+		as := gestureActions(ge.gesture)
+		if len(as) == 0 {
+			continue
+		}
+		if ge.gesture == gestureTap {
+			gameAction(as[0], true)
+			gameAction(as[0], false)
+			continue
+		}
+		pressAction(ge.seq, as[0])
+	}
+}
@@ -0,0 +1,87 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build darwin linux
+
+package flappy
+
+import (
+	"github.com/adg/game/sim"
+	"golang.org/x/mobile/exp/f32"
+	"golang.org/x/mobile/exp/sprite"
+	"golang.org/x/mobile/exp/sprite/clock"
+)
+
+// Parts of each ground tile, in the order they are drawn.
+const (
+	groundScenery = iota // scenery, standing behind the ground's edge
+	groundTop            // the top of the ground
+	groundDecor          // anything on top of the ground
+	groundEarth          // the earth beneath
+	groundParts
+)
+
+// addGround adds a node to parent that draws the ground.
+// Rather than each tile having nodes with arrangers of their own,
+// a single arranger lays out all of the tiles' nodes at once,
+// hiding those that are off screen or not in use.
+func (g *Game) addGround(eng sprite.Engine, parent *sprite.Node, texs []sprite.SubTex) {
+	var parts [sim.WorldTiles * groundParts]*sprite.Node
+	var vis [sim.WorldTiles]visibility
+	ground := &sprite.Node{Arranger: arrangerFunc(func(eng sprite.Engine, _ *sprite.Node, t clock.Time) {
+		for i := range vis {
+			x := float32(i)*sim.TileWidth - g.drawScroll()
+			n := parts[i*groundParts:]
+			if i >= g.Tiles || g.view.offScreen(x, x+sim.TileWidth) {
+				if vis[i].shown {
+					for _, p := range n[:groundParts] {
+						eng.SetSubTex(p, sprite.SubTex{})
+					}
+					vis[i].shown = false
+				}
+				continue
+			}
+			vis[i].shown = true
+			// Tiles on a slope are sheared to ramp from the tile before.
+			y0, y1 := g.SlopeY(i)
+			dy := y1 - y0
+			if s := g.Scenery[i]; s != sim.SceneryNone {
+				sc := sim.Sceneries[s]
+				eng.SetSubTex(n[groundScenery], texs[sc.Tex])
+				eng.SetTransform(n[groundScenery], f32.Affine{
+					{sc.W, 0, x + (sim.TileWidth-sc.W)/2},
+					{0, sc.H, g.GroundY[i] - sc.H + sim.TileHeight/8},
+				})
+			} else {
+				eng.SetSubTex(n[groundScenery], sprite.SubTex{})
+			}
+			eng.SetSubTex(n[groundTop], texs[g.groundTexAt(i)])
+			eng.SetTransform(n[groundTop], f32.Affine{
+				{sim.TileWidth, 0, x},
+				{dy, sim.TileHeight, y0},
+			})
+			if top, ok := g.topTex(i); ok {
+				eng.SetSubTex(n[groundDecor], texs[top])
+				eng.SetTransform(n[groundDecor], f32.Affine{
+					{sim.TileWidth, 0, x},
+					{dy, sim.TileHeight / 2, y0 - sim.TileHeight/4},
+				})
+			} else {
+				eng.SetSubTex(n[groundDecor], sprite.SubTex{})
+			}
+			eng.SetSubTex(n[groundEarth], texs[g.tex(i, sim.TexEarth)])
+			eng.SetTransform(n[groundEarth], f32.Affine{
+				{sim.TileWidth, 0, x},
+				{dy, sim.TileHeight * sim.TilesY, y0 + sim.TileHeight},
+			})
+		}
+	})}
+	eng.Register(ground)
+	parent.AppendChild(ground)
+	for i := range parts {
+		parts[i] = &sprite.Node{}
+		eng.Register(parts[i])
+		ground.AppendChild(parts[i])
+	}
+}
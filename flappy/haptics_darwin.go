@@ -0,0 +1,12 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build darwin
+
+package flappy
+
+import "time"
+
+// buzz does nothing, as vibration isn't supported on darwin yet.
+func buzz(d time.Duration) {}
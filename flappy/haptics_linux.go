@@ -0,0 +1,36 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build linux
+
+package flappy
+
+import (
+	"io/ioutil"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Vibration motors are driven through sysfs: older Android devices
+// have a timed output that runs for the milliseconds written to it,
+// and newer ones an LED class device with a duration and a trigger.
+// Desktops usually have neither, and so don't vibrate.
+
+const (
+	timedVibrator = "/sys/class/timed_output/vibrator/enable"
+	ledVibrator   = "/sys/class/leds/vibrator/"
+)
+
+// buzz runs the device's vibration motor for d, if it has one.
+func buzz(d time.Duration) {
+	ms := []byte(strconv.FormatInt(int64(d/time.Millisecond), 10))
+	if err := ioutil.WriteFile(timedVibrator, ms, 0); !os.IsNotExist(err) {
+		return
+	}
+	if err := ioutil.WriteFile(ledVibrator+"duration", ms, 0); err != nil {
+		return
+	}
+	ioutil.WriteFile(ledVibrator+"activate", []byte("1"), 0)
+}
@@ -0,0 +1,14 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build darwin linux
+
+package flappy
+
+// A headphonesEvent is headphones being plugged in or unplugged.
+// Headphones are watched on platforms that report them,
+// and their events are sent to the app's event loop.
+type headphonesEvent struct {
+	plugged bool
+}
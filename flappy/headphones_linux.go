@@ -0,0 +1,43 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build linux
+
+package flappy
+
+import (
+	"bytes"
+	"io/ioutil"
+	"time"
+)
+
+// Android reports a wired headset through the h2w switch,
+// whose state is 0 when nothing is plugged in.
+// Devices without the switch, such as desktops, are never
+// reported as having headphones.
+const headsetSwitch = "/sys/class/switch/h2w/state"
+
+const headphonesPollEvery = time.Second // how often to look at the headset switch
+
+// watchHeadphones sends a headphonesEvent whenever headphones are
+// plugged in or unplugged.
+func watchHeadphones(send func(interface{})) {
+	plugged := headphonesPlugged()
+	for range time.Tick(headphonesPollEvery) {
+		if p := headphonesPlugged(); p != plugged {
+			plugged = p
+			send(headphonesEvent{plugged})
+		}
+	}
+}
+
+// headphonesPlugged reports whether the headset switch shows headphones.
+func headphonesPlugged() bool {
+	b, err := ioutil.ReadFile(headsetSwitch)
+	if err != nil {
+		return false
+	}
+	s := string(bytes.TrimSpace(b))
+	return s != "" && s != "0"
+}
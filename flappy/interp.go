@@ -0,0 +1,33 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build darwin linux
+
+package flappy
+
+import "github.com/adg/game/sim"
+
+// lerp returns how far along from prev to cur things are drawn.
+func (g *Game) lerp(prev, cur float32) float32 {
+	if g.Paused() || g.Choosing() {
+		// The clock is held still, so show the frame as it is.
+		return cur
+	}
+	return prev + (cur-prev)*g.Frac
+}
+
+// drawScroll returns the scroll offset at which the scene is drawn.
+func (g *Game) drawScroll() float32 {
+	return g.lerp(g.Prev.ScrollX, g.Scroll.X)
+}
+
+// drawGopherY returns the y-offset at which the gopher is drawn.
+func (g *Game) drawGopherY() float32 {
+	return g.lerp(g.Prev.GopherY, g.Gopher.Y)
+}
+
+// drawGopherX returns the x-offset at which the gopher is drawn.
+func (g *Game) drawGopherX() float32 {
+	return sim.TileWidth*sim.GopherTile + g.lerp(g.Prev.GopherX, g.Gopher.Slip)
+}
@@ -0,0 +1,114 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build darwin linux
+
+package flappy
+
+import (
+	"math"
+
+	"github.com/adg/game/sim"
+	"golang.org/x/mobile/event/size"
+	"golang.org/x/mobile/exp/f32"
+)
+
+// A layout fits the world onto the screen.
+// At least sim.TilesX by sim.TilesY tiles are shown, keeping their aspect ratio.
+// Wider screens show more ground ahead of the gopher, up to sim.MaxTilesX tiles
+// across, beyond which they are letterboxed; taller screens, such as phones
+// held upright, show more sky above and earth below.
+//
+// Tiles are scaled to a whole number of the screen's pixels,
+// and the world is placed on a pixel boundary, so that the same
+// sprites look equally sharp, with no seams between the tiles,
+// at any pixel density.
+type layout struct {
+	scale float32 // screen points per world unit
+	x, y  float32 // top-left corner of the world on screen, in points
+	w     float32 // width of the world shown
+}
+
+// newLayout returns the layout for a screen of size sz.
+func newLayout(sz size.Event) layout {
+	w, h := float32(sz.WidthPt), float32(sz.HeightPt)
+	if w == 0 || h == 0 {
+		return layout{scale: 1, w: sim.WorldW}
+	}
+	s := h / sim.WorldH
+	if w/sim.WorldW < s {
+		s = w / sim.WorldW
+	}
+	if ppp := sz.PixelsPerPt; ppp > 0 {
+		tile := float32(math.Floor(float64(sim.TileWidth * s * ppp)))
+		if tile < 1 {
+			tile = 1
+		}
+		s = tile / (sim.TileWidth * ppp)
+	}
+	vw := w / s
+	if vw > sim.MaxTilesX*sim.TileWidth {
+		vw = sim.MaxTilesX * sim.TileWidth
+	}
+	if vw < sim.WorldW {
+		vw = sim.WorldW
+	}
+	return layout{
+		scale: s,
+		x:     snap((w-vw*s)/2, sz.PixelsPerPt),
+		y:     snap((h-sim.WorldH*s)/2, sz.PixelsPerPt),
+		w:     vw,
+	}
+}
+
+// snap rounds the length v, in points, to a whole number of pixels.
+func snap(v, pixelsPerPt float32) float32 {
+	if pixelsPerPt <= 0 {
+		return v
+	}
+	return float32(math.Floor(float64(v*pixelsPerPt))) / pixelsPerPt
+}
+
+// transform returns the transform from world coordinates, offset by dx and dy,
+// to screen coordinates.
+func (l layout) transform(dx, dy float32) f32.Affine {
+	return f32.Affine{
+		{l.scale, 0, l.x + dx*l.scale},
+		{0, l.scale, l.y + dy*l.scale},
+	}
+}
+
+// top returns the world y-offset of the top of the screen.
+func (l layout) top() float32 {
+	return -l.y / l.scale
+}
+
+// hudX returns the x-offset of the HUD, which is laid out
+// for sim.TilesX tiles across and kept in the middle of the screen.
+func (l layout) hudX() float32 {
+	return (l.w - sim.WorldW) / 2
+}
+
+// screenCover stretches a texture over the whole screen, whatever its layout.
+var screenCover = f32.Affine{
+	{sim.MaxTilesX * sim.TileWidth * 4, 0, -sim.MaxTilesX * sim.TileWidth * 2},
+	{0, sim.WorldH * 8, -sim.WorldH * 4},
+}
+
+// bars returns the transforms of the letterbox bars, either side of the world.
+func (l layout) bars() [2]f32.Affine {
+	const w = sim.MaxTilesX * sim.TileWidth * 2
+	return [2]f32.Affine{
+		{{w, 0, -w}, {0, sim.WorldH * 8, -sim.WorldH * 4}},
+		{{w, 0, l.w}, {0, sim.WorldH * 8, -sim.WorldH * 4}},
+	}
+}
+
+// Resize fits the game to a screen of size sz.
+// If the screen has got wider during a run, the ground is extended
+// to fill it; the next run keeps only as much ground as is needed.
+func (g *Game) Resize(sz size.Event) {
+	g.view = newLayout(sz)
+	g.SetWidth(g.view.w)
+}
@@ -0,0 +1,92 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build darwin linux
+
+package flappy
+
+import (
+	"image"
+	"image/color"
+	"math"
+
+	"github.com/adg/game/sim"
+	"golang.org/x/mobile/exp/f32"
+	"golang.org/x/mobile/exp/sprite"
+	"golang.org/x/mobile/exp/sprite/clock"
+)
+
+// paintLight returns a painter for the light around the gopher, in a darkness
+// of the given level: clear in the middle, fading to the darkness at the edges.
+func paintLight(level int) func(m *image.RGBA, r image.Rectangle) {
+	dark := float64(level) / sim.LightLevels
+	return func(m *image.RGBA, r image.Rectangle) {
+		c := r.Min.Add(r.Max).Div(2)
+		for y := r.Min.Y; y < r.Max.Y; y++ {
+			for x := r.Min.X; x < r.Max.X; x++ {
+				d := math.Hypot(float64(x-c.X)+0.5, float64(y-c.Y)+0.5) / (float64(r.Dx()) / 2)
+				f := (d - sim.LightFalloff) / (1 - sim.LightFalloff)
+				f = math.Max(0, math.Min(1, f))
+				f = f * f * (3 - 2*f) // smooth the edge of the light
+				m.SetRGBA(x, y, color.RGBA{0, 0, 0, uint8(255 * dark * f)})
+			}
+		}
+	}
+}
+
+// addLighting adds nodes to parent that darken the scene,
+// except in a pool of light around the gopher.
+func (g *Game) addLighting(eng sprite.Engine, parent *sprite.Node, texs []sprite.SubTex) {
+	// The light around the gopher.
+	light := &sprite.Node{Arranger: arrangerFunc(func(eng sprite.Engine, n *sprite.Node, t clock.Time) {
+		l := g.LightLevel()
+		if l == 0 {
+			eng.SetSubTex(n, sprite.SubTex{})
+			return
+		}
+		x, y := g.lightCentre()
+		eng.SetSubTex(n, texs[sim.TexLight1+l-1])
+		eng.SetTransform(n, f32.Affine{
+			{sim.LightRadius * 2, 0, x - sim.LightRadius},
+			{0, sim.LightRadius * 2, y - sim.LightRadius},
+		})
+	})}
+	eng.Register(light)
+	parent.AppendChild(light)
+
+	// The darkness beyond it, above, below, left, and right.
+	const far = sim.WorldW * 8
+	for i := 0; i < 4; i++ {
+		i := i
+		n := &sprite.Node{Arranger: arrangerFunc(func(eng sprite.Engine, n *sprite.Node, t clock.Time) {
+			l := g.LightLevel()
+			if l == 0 {
+				eng.SetSubTex(n, sprite.SubTex{})
+				return
+			}
+			x, y := g.lightCentre()
+			x0, y0, x1, y1 := x-sim.LightRadius, y-sim.LightRadius, x+sim.LightRadius, y+sim.LightRadius
+			var a f32.Affine
+			switch i {
+			case 0:
+				a = f32.Affine{{far * 2, 0, -far}, {0, far, y0 - far}}
+			case 1:
+				a = f32.Affine{{far * 2, 0, -far}, {0, far, y1}}
+			case 2:
+				a = f32.Affine{{far, 0, x0 - far}, {0, y1 - y0, y0}}
+			case 3:
+				a = f32.Affine{{far, 0, x1}, {0, y1 - y0, y0}}
+			}
+			eng.SetSubTex(n, skyTex(texs[sim.TexFade], float32(l)/sim.LightLevels))
+			eng.SetTransform(n, a)
+		})}
+		eng.Register(n)
+		parent.AppendChild(n)
+	}
+}
+
+// lightCentre returns the centre of the light, which follows the gopher.
+func (g *Game) lightCentre() (x, y float32) {
+	return g.drawGopherX() + sim.TileWidth/2, g.drawGopherY() + sim.TileHeight/2
+}
@@ -0,0 +1,308 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build darwin linux
+
+package flappy
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/adg/game/sim"
+	"golang.org/x/mobile/event/key"
+	"golang.org/x/mobile/exp/f32"
+	"golang.org/x/mobile/exp/sprite"
+	"golang.org/x/mobile/exp/sprite/clock"
+)
+
+// Main menu items.
+const (
+	menuPlay = iota
+	menuModes
+	menuSettings
+	menuStats
+	numMenuItems
+)
+
+var menuItems = [numMenuItems]string{"PLAY", "MODE", "SETTINGS", "STATS"}
+
+// Main menu pages.
+const (
+	pageMain = iota
+	pageSettings
+	pageStats
+)
+
+const settingsShown = 9 // most settings shown at once
+
+// A Menu is the title screen shown when the app starts.
+type Menu struct {
+	game      *Game
+	page      int  // the page shown; see pageMain and friends
+	item      int  // the item shown on the main page; see menuPlay and friends
+	setting   int  // the setting being changed; see sim.SettingVolume and friends
+	rebinding bool // is the key setting shown waiting for a key?
+	done      bool // has the player chosen to play?
+}
+
+// NewMenu returns the title screen for g.
+func NewMenu(g *Game) *Menu {
+	return &Menu{game: g}
+}
+
+// Done reports whether the player has chosen to play,
+// and readies the menu to be shown again.
+func (m *Menu) Done() bool {
+	done := m.done
+	m.done = false
+	return done
+}
+
+// Cycle shows the next or previous item, or changes the setting shown.
+func (m *Menu) Cycle(d int) {
+	switch m.page {
+	case pageMain:
+		m.item = ((m.item+d)%numMenuItems + numMenuItems) % numMenuItems
+	case pageSettings:
+		if _, ok := sim.KeySettings[m.setting]; ok {
+			m.rebinding = true
+			return
+		}
+		m.game.SetSettings(m.game.ChangeSetting(m.setting, d))
+	}
+}
+
+// Rebinding reports whether the menu is waiting for a key to rebind.
+func (m *Menu) Rebinding() bool {
+	return m.rebinding
+}
+
+// Rebind binds the key c to the action of the key setting shown.
+// Keys that can't be bound are ignored, and the menu keeps waiting.
+func (m *Menu) Rebind(c key.Code) {
+	name, ok := keyName(c)
+	if !ok {
+		return
+	}
+	m.rebinding = false
+	m.game.SetSettings(m.game.Settings().RebindKey(sim.KeySettings[m.setting], name))
+}
+
+// Press chooses the item shown, moves on to the next setting,
+// or goes back from a page.
+func (m *Menu) Press() {
+	if m.rebinding {
+		m.rebinding = false
+		return
+	}
+	switch m.page {
+	case pageSettings:
+		if m.setting++; m.setting == sim.NumSettings {
+			m.Back()
+		}
+		return
+	case pageStats:
+		m.Back()
+		return
+	}
+	switch m.item {
+	case menuPlay:
+		m.done = true
+	case menuModes:
+		m.game.CycleMode()
+	case menuSettings:
+		m.page = pageSettings
+		m.setting = sim.SettingVolume
+	case menuStats:
+		m.page = pageStats
+	}
+}
+
+// Back goes back to the main page.
+func (m *Menu) Back() {
+	m.rebinding = false
+	m.page = pageMain
+}
+
+// firstSetting returns the first of the settings shown,
+// which scroll to keep the one being changed in the middle.
+func (m *Menu) firstSetting() int {
+	i := m.setting - settingsShown/2
+	if i > sim.NumSettings-settingsShown {
+		i = sim.NumSettings - settingsShown
+	}
+	if i < 0 {
+		i = 0
+	}
+	return i
+}
+
+// itemText returns the item or setting being shown.
+func (m *Menu) itemText() string {
+	switch m.page {
+	case pageSettings:
+		if m.setting == sim.NumSettings-1 {
+			return "PRESS FOR MENU"
+		}
+		return "PRESS FOR NEXT"
+	case pageStats:
+		return "BACK"
+	}
+	if m.item == menuModes {
+		return "< MODE " + strings.ToUpper(m.game.Mode.String()) + " >"
+	}
+	return "< " + menuItems[m.item] + " >"
+}
+
+// statsLines returns the lines of the stats page.
+func (m *Menu) statsLines() []string {
+	s := m.game.Stats()
+	return []string{
+		"RUN " + strconv.Itoa(s.Distance),
+		"JUMPS " + strconv.Itoa(s.Jumps),
+		"FLAPS " + strconv.Itoa(s.Flaps),
+		"COINS " + strconv.Itoa(s.Coins),
+		"DEATHS " + strconv.Itoa(s.Deaths),
+		"CLIMBS " + strconv.Itoa(s.Climbs),
+		fmt.Sprintf("PLAYED %dH %02dM", int(s.PlayTime.Hours()), int(s.PlayTime.Minutes())%60),
+	}
+}
+
+// Scene returns the title screen's scene.
+func (m *Menu) Scene(eng sprite.Engine) *sprite.Node {
+	texs, font := m.game.assets(eng)
+
+	// The scene is fitted to the screen, like the game's.
+	scene := &sprite.Node{Arranger: arrangerFunc(func(eng sprite.Engine, n *sprite.Node, t clock.Time) {
+		eng.SetTransform(n, m.game.view.transform(0, 0))
+	})}
+	eng.Register(scene)
+	eng.SetTransform(scene, m.game.view.transform(0, 0))
+
+	parent := scene
+	newNode := func(fn arrangerFunc) {
+		n := &sprite.Node{Arranger: arrangerFunc(fn)}
+		eng.Register(n)
+		parent.AppendChild(n)
+	}
+
+	// The sky, which is always day on the title screen.
+	newNode(func(eng sprite.Engine, n *sprite.Node, t clock.Time) {
+		eng.SetSubTex(n, skyTex(texs[sim.TexSky], 0))
+		eng.SetTransform(n, screenCover)
+	})
+
+	// The ground.
+	for i := 0; i < sim.MaxTilesX; i++ {
+		x := float32(i * sim.TileWidth)
+		newNode(func(eng sprite.Engine, n *sprite.Node, t clock.Time) {
+			eng.SetSubTex(n, texs[sim.TexGround1])
+			eng.SetTransform(n, f32.Affine{
+				{sim.TileWidth, 0, x},
+				{0, sim.TileHeight, sim.InitGroundY},
+			})
+		})
+		newNode(func(eng sprite.Engine, n *sprite.Node, t clock.Time) {
+			eng.SetSubTex(n, texs[sim.TexEarth])
+			eng.SetTransform(n, f32.Affine{
+				{sim.TileWidth, 0, x},
+				{0, sim.TileHeight * sim.TilesY, sim.InitGroundY + sim.TileHeight},
+			})
+		})
+	}
+
+	// The rest is laid out for sim.TilesX tiles across,
+	// and kept in the middle of the screen.
+	ui := &sprite.Node{Arranger: arrangerFunc(func(eng sprite.Engine, n *sprite.Node, t clock.Time) {
+		eng.SetTransform(n, f32.Affine{
+			{1, 0, m.game.view.hudX()},
+			{0, 1, 0},
+		})
+	})}
+	eng.Register(ui)
+	scene.AppendChild(ui)
+	parent = ui
+
+	// The logo: the chosen character flapping above the title.
+	var anim animator
+	newNode(func(eng sprite.Engine, n *sprite.Node, t clock.Time) {
+		if m.page != pageMain {
+			eng.SetSubTex(n, sprite.SubTex{})
+			return
+		}
+		eng.SetSubTex(n, texs[m.game.gopherTex(anim.tex(animFlap, t))])
+		eng.SetTransform(n, f32.Affine{
+			{sim.TileWidth * 2, 0, sim.TileWidth * (sim.TilesX/2 - 1)},
+			{0, sim.TileHeight * 2, sim.TileHeight*2 + float32(sim.Frame(t, 16, 0, 1, 2, 1))*2},
+		})
+	})
+	newText(eng, ui, font, f32.Affine{
+		{glyphWidth * 3, 0, sim.TileWidth * sim.TilesX / 2},
+		{0, glyphHeight * 3, sim.TileHeight * 5},
+	}, 13, alignCenter, func() string {
+		switch m.page {
+		case pageSettings:
+			return "SETTINGS"
+		case pageStats:
+			return "STATS"
+		}
+		return "FLAPPY GOPHER"
+	})
+
+	// The settings around the one being changed, which is between arrows.
+	for j := 0; j < settingsShown; j++ {
+		j := j
+		newText(eng, ui, font, f32.Affine{
+			{glyphWidth, 0, sim.TileWidth * sim.TilesX / 2},
+			{0, glyphHeight, sim.TileHeight*13/2 + glyphHeight*float32(j)*9/8},
+		}, 21, alignCenter, func() string {
+			i := m.firstSetting() + j
+			if m.page != pageSettings || i >= sim.NumSettings {
+				return ""
+			}
+			if i == m.setting && m.rebinding {
+				return "PRESS NEW " + strings.ToUpper(sim.ActionNames[sim.KeySettings[i]]) + " KEY"
+			}
+			s := sim.SettingText(m.game.Settings(), i)
+			if i == m.setting {
+				return "< " + s + " >"
+			}
+			return s
+		})
+	}
+
+	// The lifetime statistics.
+	for i := range m.statsLines() {
+		i := i
+		newText(eng, ui, font, f32.Affine{
+			{glyphWidth, 0, sim.TileWidth * sim.TilesX / 2},
+			{0, glyphHeight, sim.TileHeight*7 + glyphHeight*float32(i)*3/2},
+		}, 16, alignCenter, func() string {
+			if m.page != pageStats {
+				return ""
+			}
+			return m.statsLines()[i]
+		})
+	}
+
+	// The item shown.
+	newText(eng, ui, font, f32.Affine{
+		{glyphWidth, 0, sim.TileWidth * sim.TilesX / 2},
+		{0, glyphHeight, sim.TileHeight*sim.TilesY*2/3 + glyphHeight*2},
+	}, 24, alignCenter, m.itemText)
+
+	parent = scene
+	// The letterbox bars, either side of the world.
+	for i := 0; i < 2; i++ {
+		i := i
+		newNode(func(eng sprite.Engine, n *sprite.Node, t clock.Time) {
+			eng.SetSubTex(n, skyTex(texs[sim.TexFade], 1))
+			eng.SetTransform(n, m.game.view.bars()[i])
+		})
+	}
+
+	return scene
+}
@@ -0,0 +1,199 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build darwin linux
+
+package flappy
+
+import (
+	"io"
+	"log"
+	"time"
+
+	"golang.org/x/mobile/exp/audio/al"
+)
+
+const (
+	musicBufferSize = 16 << 10        // bytes of samples in each streamed buffer
+	musicBuffers    = 3               // buffers queued ahead on each track
+	crossfadeTime   = 3 * time.Second // how long one track takes to fade into another
+)
+
+// A musicTrack streams a looping track through its own OpenAL source,
+// so that two tracks may be heard at once while crossfading.
+type musicTrack struct {
+	stream  pcmStream
+	source  al.Source
+	buffers []al.Buffer
+	playing bool
+	fade    ramp // how far the track has faded in
+}
+
+// A musicPlayer plays the soundtrack, fading between tracks.
+type musicPlayer struct {
+	on     bool                   // has the player been started?
+	tracks map[string]*musicTrack // tracks opened so far, by name
+	want   string                 // the track to fade in, or "" for silence
+	buf    []byte
+}
+
+// start readies the player, once the audio device is open.
+func (m *musicPlayer) start() {
+	m.on = true
+	m.tracks = make(map[string]*musicTrack)
+}
+
+// stop closes the tracks, before the audio device is closed.
+func (m *musicPlayer) stop() {
+	if !m.on {
+		return
+	}
+	for _, t := range m.tracks {
+		al.StopSources(t.source)
+		al.DeleteSources(t.source)
+		al.DeleteBuffers(t.buffers...)
+		t.stream.Close()
+	}
+	*m = musicPlayer{buf: m.buf}
+}
+
+// play fades in the track called name, or fades out all tracks if name is "".
+// A track fading in starts from its beginning, unless it was still fading out.
+func (m *musicPlayer) play(name string) {
+	if !m.on || name == m.want {
+		return
+	}
+	m.want = name
+	if name == "" {
+		return
+	}
+	t, ok := m.tracks[name]
+	if !ok {
+		s, err := openSound(name)
+		if err != nil {
+			log.Print(err)
+			return
+		}
+		t = &musicTrack{stream: s, source: al.GenSources(1)[0], buffers: al.GenBuffers(musicBuffers)}
+		m.tracks[name] = t
+	}
+	if t.playing {
+		return
+	}
+	if err := t.stream.rewind(); err != nil {
+		log.Print(err)
+		return
+	}
+	for _, b := range t.buffers {
+		m.fill(t, b)
+	}
+	t.source.QueueBuffers(t.buffers...)
+	t.source.SetGain(0)
+	al.PlaySources(t.source)
+	t.playing, t.fade = true, ramp{time: crossfadeTime}
+}
+
+// update fades the tracks in and out over the time dt since it was last called,
+// plays them at volume and pitch, and streams more of them.
+func (m *musicPlayer) update(dt time.Duration, volume, pitch float32) {
+	if !m.on {
+		return
+	}
+	for name, t := range m.tracks {
+		if !t.playing {
+			continue
+		}
+		t.fade.target = 0
+		if name == m.want {
+			t.fade.target = 1
+		}
+		t.fade.step(dt)
+		if t.fade.level == 0 && t.fade.target == 0 {
+			m.halt(t)
+			continue
+		}
+		t.source.SetGain(t.fade.level * volume)
+		t.source.Setf(alPitch, pitch)
+		m.stream(t)
+	}
+}
+
+// stream refills the buffers the track has played,
+// and restarts it if it ran dry.
+func (m *musicPlayer) stream(t *musicTrack) {
+	n := t.source.BuffersProcessed()
+	if n == 0 {
+		return
+	}
+	bs := make([]al.Buffer, n)
+	t.source.UnqueueBuffers(bs...)
+	for _, b := range bs {
+		m.fill(t, b)
+	}
+	t.source.QueueBuffers(bs...)
+	if t.source.State() != al.Playing {
+		al.PlaySources(t.source)
+	}
+}
+
+// pause pauses the tracks playing, keeping their place.
+func (m *musicPlayer) pause() {
+	for _, t := range m.tracks {
+		if t.playing {
+			al.PauseSources(t.source)
+		}
+	}
+}
+
+// resume carries on playing the tracks paused by pause.
+func (m *musicPlayer) resume() {
+	for _, t := range m.tracks {
+		if t.playing {
+			al.PlaySources(t.source)
+		}
+	}
+}
+
+// halt stops the track, and takes back its buffers.
+func (m *musicPlayer) halt(t *musicTrack) {
+	al.StopSources(t.source)
+	if n := t.source.BuffersQueued(); n > 0 {
+		t.source.UnqueueBuffers(make([]al.Buffer, n)...)
+	}
+	t.playing = false
+}
+
+// fill reads the next buffer's worth of the track into b,
+// going back to the start of the track at its end, so that it loops
+// without a gap.
+func (m *musicPlayer) fill(t *musicTrack, b al.Buffer) {
+	if m.buf == nil {
+		m.buf = make([]byte, musicBufferSize)
+	}
+	n, looped := 0, false
+read:
+	for n < len(m.buf) {
+		k, err := t.stream.Read(m.buf[n:])
+		n += k
+		if k > 0 {
+			looped = false
+		}
+		switch {
+		case err == io.EOF && looped:
+			// The track is empty.
+			break read
+		case err == io.EOF:
+			if err := t.stream.rewind(); err != nil {
+				log.Print(err)
+				break read
+			}
+			looped = true
+		case err != nil:
+			log.Print(err)
+			break read
+		}
+	}
+	format, rate := t.stream.format()
+	b.BufferData(format, m.buf[:n], rate)
+}
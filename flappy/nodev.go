@@ -0,0 +1,12 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build darwin linux
+// +build !dev
+
+package flappy
+
+// assetsChanged reports whether the assets have changed.
+// Only development builds watch for changes.
+func assetsChanged() bool { return false }
@@ -0,0 +1,52 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build darwin linux
+
+package flappy
+
+import (
+	"math"
+
+	"github.com/adg/game/sim"
+	"golang.org/x/mobile/exp/f32"
+	"golang.org/x/mobile/exp/sprite"
+	"golang.org/x/mobile/exp/sprite/clock"
+)
+
+// A layer is scenery that scrolls at a fraction of the ground's speed,
+// repeating its texture across the screen.
+type layer struct {
+	tex   int     // texture
+	speed float32 // fraction of the ground's speed at which it scrolls
+	y     float32 // y-offset of the top of the layer
+	w, h  float32 // size of each repeat of the texture
+}
+
+var (
+	clouds = layer{sim.TexCloud, 0.1, sim.TileHeight * 2, sim.TileWidth * 5, sim.TileHeight * 2}
+	hills  = layer{sim.TexHills, 0.25, sim.TileHeight * (sim.TilesY - 9), sim.TileWidth * 8, sim.TileHeight * 9}
+	grass  = layer{sim.TexGrass, 1.5, sim.TileHeight * (sim.TilesY - 1), sim.TileWidth * 4, sim.TileHeight}
+)
+
+// addLayer adds nodes to draw l using newNode.
+func (g *Game) addLayer(newNode func(arrangerFunc), texs []sprite.SubTex, l layer) {
+	n := int(sim.MaxTilesX*sim.TileWidth/l.w) + 2
+	for i := 0; i < n; i++ {
+		i := i
+		var vis visibility
+		newNode(func(eng sprite.Engine, n *sprite.Node, t clock.Time) {
+			off := float32(math.Mod(float64(g.Travelled*l.speed), float64(l.w)))
+			x := float32(i)*l.w - off
+			if g.view.offScreen(x, x+l.w) {
+				vis.hide(eng, n)
+				return
+			}
+			vis.show(eng, n, texs[l.tex], f32.Affine{
+				{l.w, 0, x},
+				{0, l.h, l.y},
+			})
+		})
+	}
+}
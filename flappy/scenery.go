@@ -0,0 +1,44 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build darwin linux
+
+package flappy
+
+import (
+	"image"
+	"image/color"
+)
+
+func paintTree(m *image.RGBA, r image.Rectangle) {
+	d := r.Dx() / 8
+	outlined(m, image.Rect(r.Min.X+d*7/2-outline, r.Min.Y+d*4, r.Min.X+d*9/2+outline, r.Max.Y), brown, fillRect)
+	leaves := color.RGBA{0x2a, 0x80, 0x36, 0xff}
+	outlined(m, image.Rect(r.Min.X+d, r.Min.Y, r.Max.X-d, r.Min.Y+d*5), leaves, fillEllipse)
+}
+
+func paintPebbles(m *image.RGBA, r image.Rectangle) {
+	d := r.Dx() / 8
+	outlined(m, image.Rect(r.Min.X+d, r.Max.Y-d*3, r.Min.X+d*4, r.Max.Y), grey, fillEllipse)
+	outlined(m, image.Rect(r.Min.X+d*4, r.Max.Y-d*2, r.Min.X+d*6, r.Max.Y), grey, fillEllipse)
+}
+
+func paintFlowers(m *image.RGBA, r image.Rectangle) {
+	d := r.Dx() / 8
+	for i, c := range []color.RGBA{gold, white, plum} {
+		x := r.Min.X + d + i*d*5/2
+		top := r.Max.Y - d*4 + d*(i%2)
+		fillRect(m, image.Rect(x+d/2, top+d, x+d/2+2, r.Max.Y), color.RGBA{0x1e, 0x6e, 0x2a, 0xff})
+		outlined(m, image.Rect(x-d/2, top-d/2, x+d*3/2, top+d*3/2), c, fillEllipse)
+	}
+}
+
+func paintFence(m *image.RGBA, r image.Rectangle) {
+	d := r.Dx() / 8
+	outlined(m, image.Rect(r.Min.X, r.Min.Y+d*2, r.Max.X, r.Min.Y+d*3+outline), tan, fillRect)
+	outlined(m, image.Rect(r.Min.X, r.Min.Y+d*5, r.Max.X, r.Min.Y+d*6+outline), tan, fillRect)
+	for _, x := range []int{r.Min.X + d, r.Max.X - d*2 - outline} {
+		outlined(m, image.Rect(x, r.Min.Y, x+d+outline, r.Max.Y), tan, fillRect)
+	}
+}
@@ -0,0 +1,68 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build darwin linux
+
+package flappy
+
+import (
+	"fmt"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/adg/game/sim"
+	"golang.org/x/mobile/event/size"
+	"golang.org/x/mobile/gl"
+)
+
+// screenshotWanted is set when the next frame drawn should be saved.
+var screenshotWanted bool
+
+// TakeScreenshot saves the next frame drawn as a PNG in the app's storage,
+// for sharing high scores and reporting visual bugs.
+func TakeScreenshot() {
+	screenshotWanted = true
+}
+
+func screenshotDir() string {
+	return filepath.Join(sim.DataDir(), "screenshots")
+}
+
+// saveScreenshot reads the frame just drawn from the framebuffer
+// and writes it to a new PNG, returning the file's name.
+func saveScreenshot(glctx gl.Context, sz size.Event) (string, error) {
+	w, h := sz.WidthPx, sz.HeightPx
+	if w == 0 || h == 0 {
+		return "", fmt.Errorf("screenshot of empty screen")
+	}
+	pix := make([]byte, w*h*4)
+	glctx.ReadPixels(pix, 0, 0, w, h, gl.RGBA, gl.UNSIGNED_BYTE)
+
+	// OpenGL reads from the bottom row up.
+	m := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		copy(m.Pix[y*m.Stride:(y+1)*m.Stride], pix[(h-1-y)*w*4:(h-y)*w*4])
+	}
+	for i := 3; i < len(m.Pix); i += 4 {
+		m.Pix[i] = 0xff // the screen itself is opaque
+	}
+
+	dir := screenshotDir()
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", err
+	}
+	name := filepath.Join(dir, time.Now().Format("flappy-20060102-150405.000.png"))
+	f, err := os.Create(name)
+	if err != nil {
+		return "", err
+	}
+	if err := png.Encode(f, m); err != nil {
+		f.Close()
+		return "", err
+	}
+	return name, f.Close()
+}
@@ -0,0 +1,59 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build darwin linux
+
+package flappy
+
+import (
+	"image"
+	"image/color"
+
+	"golang.org/x/mobile/exp/sprite"
+)
+
+// skyColors are the colours of the sky through the day,
+// keyed by the fraction of the day that has passed.
+var skyColors = []struct {
+	at float32
+	c  color.RGBA
+}{
+	{0.00, color.RGBA{0x8f, 0xd3, 0xf5, 0xff}}, // day
+	{0.40, color.RGBA{0x8f, 0xd3, 0xf5, 0xff}},
+	{0.45, color.RGBA{0xf0, 0x9a, 0x5a, 0xff}}, // dusk
+	{0.50, color.RGBA{0x16, 0x1e, 0x3c, 0xff}}, // night
+	{0.90, color.RGBA{0x16, 0x1e, 0x3c, 0xff}},
+	{0.95, color.RGBA{0xf3, 0xb4, 0xc6, 0xff}}, // dawn
+	{1.00, color.RGBA{0x8f, 0xd3, 0xf5, 0xff}},
+}
+
+// skyColor returns the colour of the sky at the given time of day.
+func skyColor(day float32) color.RGBA {
+	for i := 1; i < len(skyColors); i++ {
+		a, b := skyColors[i-1], skyColors[i]
+		if day > b.at {
+			continue
+		}
+		f := (day - a.at) / (b.at - a.at)
+		mix := func(x, y uint8) uint8 { return uint8(float32(x) + (float32(y)-float32(x))*f) }
+		return color.RGBA{mix(a.c.R, b.c.R), mix(a.c.G, b.c.G), mix(a.c.B, b.c.B), 0xff}
+	}
+	return skyColors[len(skyColors)-1].c
+}
+
+// paintSky paints the colour of the sky through the day from left to right.
+func paintSky(m *image.RGBA, r image.Rectangle) {
+	for x := r.Min.X; x < r.Max.X; x++ {
+		c := skyColor(float32(x-r.Min.X) / float32(r.Dx()))
+		fillRect(m, image.Rect(x, r.Min.Y, x+1, r.Max.Y), c)
+	}
+}
+
+// skyTex returns the one pixel wide slice of the sky texture x
+// for the given time of day.
+func skyTex(x sprite.SubTex, day float32) sprite.SubTex {
+	col := x.R.Min.X + int(day*float32(x.R.Dx()-1))
+	x.R = image.Rect(col, x.R.Min.Y, col+1, x.R.Max.Y)
+	return x
+}
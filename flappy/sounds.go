@@ -0,0 +1,108 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build darwin linux
+
+package flappy
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"math/rand"
+
+	"golang.org/x/mobile/asset"
+	"golang.org/x/mobile/exp/audio/al"
+)
+
+// A soundEffect describes how a sound effect is played.
+// Having a few takes of an effect, and varying their pitch,
+// keeps effects heard again and again from grating.
+type soundEffect struct {
+	Takes []string // audio assets, one picked at random each time; see openSound
+	Pitch float32  // most the pitch varies either way, as a fraction of it
+}
+
+// loadSoundEffects reads how each sound effect is played from sounds.json.
+// Effects it doesn't describe, or all of them if it is missing or invalid,
+// have a single take named after the effect, played at a steady pitch.
+func loadSoundEffects() map[string]soundEffect {
+	es := make(map[string]soundEffect)
+	if a, err := asset.Open("sounds.json"); err != nil {
+		log.Print(err)
+	} else {
+		err := json.NewDecoder(a).Decode(&es)
+		a.Close()
+		if err == nil {
+			err = validateSoundEffects(es)
+		}
+		if err != nil {
+			log.Printf("sounds.json: %v", err)
+			es = make(map[string]soundEffect)
+		}
+	}
+	for _, name := range soundEffects {
+		if _, ok := es[name]; !ok {
+			es[name] = soundEffect{Takes: []string{name}}
+		}
+	}
+	return es
+}
+
+func validateSoundEffects(es map[string]soundEffect) error {
+	for name, e := range es {
+		if len(e.Takes) == 0 {
+			return fmt.Errorf("%s: no takes", name)
+		}
+		if e.Pitch < 0 || e.Pitch >= 0.5 {
+			return fmt.Errorf("%s: pitch variation %v out of range", name, e.Pitch)
+		}
+	}
+	return nil
+}
+
+// A loadedEffect is a sound effect whose takes are loaded into buffers.
+type loadedEffect struct {
+	takes []al.Buffer
+	pitch float32
+}
+
+// loadEffect loads the takes of e, skipping any that can't be loaded.
+func loadEffect(e soundEffect) loadedEffect {
+	l := loadedEffect{pitch: e.Pitch}
+	for _, name := range e.Takes {
+		b, err := loadTake(name)
+		if err != nil {
+			log.Print(err)
+			continue
+		}
+		l.takes = append(l.takes, b)
+	}
+	return l
+}
+
+// loadTake loads the whole of the audio asset called name into a buffer.
+func loadTake(name string) (al.Buffer, error) {
+	s, err := openSound(name)
+	if err != nil {
+		return 0, err
+	}
+	defer s.Close()
+	b, err := ioutil.ReadAll(s)
+	if err != nil {
+		return 0, err
+	}
+	buf := al.GenBuffers(1)[0]
+	format, rate := s.format()
+	buf.BufferData(format, b, rate)
+	return buf, nil
+}
+
+// pick returns a take of the effect, and the pitch to play it at.
+// Sound effects vary by chance alone, leaving the world's random numbers be.
+func (l loadedEffect) pick() (al.Buffer, float32) {
+	b := l.takes[rand.Intn(len(l.takes))]
+	return b, 1 + l.pitch*(2*rand.Float32()-1)
+}
@@ -0,0 +1,56 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build darwin linux
+
+package flappy
+
+import (
+	"image"
+	"image/draw"
+	"log"
+
+	"github.com/adg/game/sim"
+	"golang.org/x/mobile/exp/sprite"
+)
+
+// starTints are the colours the gopher cycles through while it has the star.
+var starTints = [...]func(r, g, b float32) (float32, float32, float32){
+	func(r, g, b float32) (float32, float32, float32) { return r * 1.6, g * 0.5, b * 0.5 },
+	func(r, g, b float32) (float32, float32, float32) { return r * 1.5, g * 1.4, b * 0.4 },
+	func(r, g, b float32) (float32, float32, float32) { return r * 0.5, g * 1.5, b * 0.6 },
+	func(r, g, b float32) (float32, float32, float32) { return r * 0.8, g * 0.6, b * 1.7 },
+}
+
+// starSets is the first of the tinted gopher textures,
+// which follow the biomes' ground textures.
+const starSets = sim.TexCount + sim.NumBiomes*2*groundSetSize
+
+// loadStarSets loads a tinted set of gopher textures for each of starTints.
+func loadStarSets(eng sprite.Engine, m image.Image, texs []sprite.SubTex) []sprite.SubTex {
+	r := texs[sim.TexGopherRun1].R
+	for x := sim.TexGopherRun1; x <= sim.TexGopherGlide; x++ {
+		r = r.Union(texs[x].R)
+	}
+	gopher := image.NewRGBA(image.Rect(0, 0, r.Dx(), r.Dy()))
+	draw.Draw(gopher, gopher.Bounds(), m, r.Min, draw.Src)
+
+	var sets []sprite.SubTex
+	for _, tint := range starTints {
+		t, err := eng.LoadTexture(recolor(gopher, tint))
+		if err != nil {
+			log.Fatal(err)
+		}
+		for x := sim.TexGopherRun1; x <= sim.TexGopherGlide; x++ {
+			sets = append(sets, sprite.SubTex{t, texs[x].R.Sub(r.Min)})
+		}
+	}
+	return sets
+}
+
+// starTex returns the tinted variant of gopher texture x to show at frame t.
+func starTex(x, t int) int {
+	tint := t / sim.StarCycle % len(starTints)
+	return starSets + tint*sim.GopherSetSize + x - sim.TexGopherRun1
+}
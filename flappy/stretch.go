@@ -0,0 +1,28 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build darwin linux
+
+package flappy
+
+import (
+	"github.com/adg/game/sim"
+	"golang.org/x/mobile/exp/f32"
+	"golang.org/x/mobile/exp/sprite/clock"
+)
+
+// stretchGopherAffine squashes or stretches the gopher drawn with a at t,
+// keeping its feet where they are and its volume about the same.
+func (g *Game) stretchGopherAffine(a *f32.Affine, t clock.Time) {
+	dt := t - g.Stretch.Start
+	if dt < 0 || dt >= sim.StretchTime || g.Stretch.Amount == 0 {
+		return
+	}
+	f := 1 - float32(dt)/sim.StretchTime
+	s := g.Stretch.Amount * f * f
+	const feet = 7.0 / 8 // where the gopher's feet are in its sprite
+	a.Translate(a, 0.5, feet)
+	a.Scale(a, 1/(1+s), 1+s)
+	a.Translate(a, -0.5, -feet)
+}
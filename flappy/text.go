@@ -0,0 +1,152 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build darwin linux
+
+package flappy
+
+import (
+	"image"
+	"image/draw"
+	"log"
+
+	"golang.org/x/mobile/exp/f32"
+	"golang.org/x/mobile/exp/sprite"
+	"golang.org/x/mobile/exp/sprite/clock"
+)
+
+const (
+	glyphWidth, glyphHeight = 6, 8 // size of each glyph cell, in font pixels
+	glyphScale              = 4    // texture pixels per font pixel
+)
+
+// glyphs holds a 5x7 bitmap for each printable character.
+var glyphs = map[byte][7]string{
+	'+': {".....", "..#..", "..#..", "#####", "..#..", "..#..", "....."},
+	'-': {".....", ".....", ".....", "#####", ".....", ".....", "....."},
+	'.': {".....", ".....", ".....", ".....", ".....", ".##..", ".##.."},
+	'/': {"....#", "....#", "...#.", "..#..", ".#...", "#....", "#...."},
+	'0': {".###.", "#...#", "#..##", "#.#.#", "##..#", "#...#", ".###."},
+	'1': {"..#..", ".##..", "..#..", "..#..", "..#..", "..#..", ".###."},
+	'2': {".###.", "#...#", "....#", "...#.", "..#..", ".#...", "#####"},
+	'3': {"#####", "...#.", "..#..", "...#.", "....#", "#...#", ".###."},
+	'4': {"...#.", "..##.", ".#.#.", "#..#.", "#####", "...#.", "...#."},
+	'5': {"#####", "#....", "####.", "....#", "....#", "#...#", ".###."},
+	'6': {"..##.", ".#...", "#....", "####.", "#...#", "#...#", ".###."},
+	'7': {"#####", "....#", "...#.", "..#..", ".#...", ".#...", ".#..."},
+	'8': {".###.", "#...#", "#...#", ".###.", "#...#", "#...#", ".###."},
+	'9': {".###.", "#...#", "#...#", ".####", "....#", "...#.", ".##.."},
+	'!': {"..#..", "..#..", "..#..", "..#..", "..#..", ".....", "..#.."},
+	'<': {"...#.", "..#..", ".#...", "#....", ".#...", "..#..", "...#."},
+	'>': {".#...", "..#..", "...#.", "....#", "...#.", "..#..", ".#..."},
+	'A': {".###.", "#...#", "#...#", "#####", "#...#", "#...#", "#...#"},
+	'B': {"####.", "#...#", "#...#", "####.", "#...#", "#...#", "####."},
+	'C': {".###.", "#...#", "#....", "#....", "#....", "#...#", ".###."},
+	'D': {"####.", "#...#", "#...#", "#...#", "#...#", "#...#", "####."},
+	'E': {"#####", "#....", "#....", "####.", "#....", "#....", "#####"},
+	'F': {"#####", "#....", "#....", "####.", "#....", "#....", "#...."},
+	'G': {".###.", "#...#", "#....", "#.###", "#...#", "#...#", ".####"},
+	'H': {"#...#", "#...#", "#...#", "#####", "#...#", "#...#", "#...#"},
+	'I': {".###.", "..#..", "..#..", "..#..", "..#..", "..#..", ".###."},
+	'J': {"..###", "...#.", "...#.", "...#.", "...#.", "#..#.", ".##.."},
+	'K': {"#...#", "#..#.", "#.#..", "##...", "#.#..", "#..#.", "#...#"},
+	'L': {"#....", "#....", "#....", "#....", "#....", "#....", "#####"},
+	'M': {"#...#", "##.##", "#.#.#", "#.#.#", "#...#", "#...#", "#...#"},
+	'N': {"#...#", "#...#", "##..#", "#.#.#", "#..##", "#...#", "#...#"},
+	'O': {".###.", "#...#", "#...#", "#...#", "#...#", "#...#", ".###."},
+	'P': {"####.", "#...#", "#...#", "####.", "#....", "#....", "#...."},
+	'Q': {".###.", "#...#", "#...#", "#...#", "#.#.#", "#..#.", ".##.#"},
+	'R': {"####.", "#...#", "#...#", "####.", "#.#..", "#..#.", "#...#"},
+	'S': {".####", "#....", "#....", ".###.", "....#", "....#", "####."},
+	'T': {"#####", "..#..", "..#..", "..#..", "..#..", "..#..", "..#.."},
+	'U': {"#...#", "#...#", "#...#", "#...#", "#...#", "#...#", ".###."},
+	'V': {"#...#", "#...#", "#...#", "#...#", "#...#", ".#.#.", "..#.."},
+	'W': {"#...#", "#...#", "#...#", "#.#.#", "#.#.#", "#.#.#", ".#.#."},
+	'X': {"#...#", "#...#", ".#.#.", "..#..", ".#.#.", "#...#", "#...#"},
+	'Y': {"#...#", "#...#", ".#.#.", "..#..", "..#..", "..#..", "..#.."},
+	'Z': {"#####", "....#", "...#.", "..#..", ".#...", "#....", "#####"},
+}
+
+// font maps characters to their sub-textures.
+type font [128]sprite.SubTex
+
+// loadFont renders glyphs into a texture,
+// in white with a black outline so that they show up on any sky.
+func loadFont(eng sprite.Engine) *font {
+	const w, h = glyphWidth * glyphScale, glyphHeight * glyphScale
+	m := image.NewRGBA(image.Rect(0, 0, w*len(glyphs), h))
+	rects := make(map[byte]image.Rectangle)
+	i := 0
+	for c, rows := range glyphs {
+		r := image.Rect(w*i, 0, w*(i+1), h)
+		for _, col := range []*image.Uniform{image.Black, image.White} {
+			for y, row := range rows {
+				for x := range row {
+					if row[x] != '#' {
+						continue
+					}
+					p := r.Min.Add(image.Pt(x, y).Mul(glyphScale)).Add(image.Pt(1, 1))
+					px := image.Rectangle{p, p.Add(image.Pt(glyphScale, glyphScale))}
+					if col == image.Black {
+						px = px.Inset(-1)
+					}
+					draw.Draw(m, px, col, image.ZP, draw.Src)
+				}
+			}
+		}
+		rects[c] = r
+		i++
+	}
+	t, err := eng.LoadTexture(m)
+	if err != nil {
+		log.Fatal(err)
+	}
+	var f font
+	for c, r := range rects {
+		f[c] = sprite.SubTex{t, r}
+	}
+	return &f
+}
+
+// textAlign specifies how text is positioned relative to its anchor.
+type textAlign int
+
+const (
+	alignLeft textAlign = iota
+	alignCenter
+)
+
+// newText adds a node to parent that displays the string returned by s,
+// one glyph per child, for up to n characters.
+// The transform a maps a single glyph to the anchor of the text.
+func newText(eng sprite.Engine, parent *sprite.Node, f *font, a f32.Affine, n int, align textAlign, s func() string) {
+	var str string
+	t := &sprite.Node{Arranger: arrangerFunc(func(eng sprite.Engine, n *sprite.Node, _ clock.Time) {
+		str = s()
+		if align == alignCenter {
+			b := a
+			b.Translate(&b, -float32(len(str))/2, 0)
+			eng.SetTransform(n, b)
+		}
+	})}
+	eng.Register(t)
+	eng.SetTransform(t, a)
+	parent.AppendChild(t)
+	for i := 0; i < n; i++ {
+		i := i
+		c := &sprite.Node{Arranger: arrangerFunc(func(eng sprite.Engine, n *sprite.Node, _ clock.Time) {
+			var x sprite.SubTex
+			if i < len(str) && str[i] < byte(len(f)) {
+				x = f[str[i]]
+			}
+			eng.SetSubTex(n, x)
+		})}
+		eng.Register(c)
+		eng.SetTransform(c, f32.Affine{
+			{1, 0, float32(i)},
+			{0, 1, 0},
+		})
+		t.AppendChild(c)
+	}
+}
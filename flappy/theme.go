@@ -0,0 +1,19 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build darwin linux
+
+package flappy
+
+import (
+	"image"
+	"image/draw"
+)
+
+// tintRect applies tint to the pixels of m within r.
+func tintRect(m *image.RGBA, r image.Rectangle, tint func(r, g, b float32) (float32, float32, float32)) {
+	part := image.NewRGBA(r)
+	draw.Draw(part, r, m, r.Min, draw.Src)
+	draw.Draw(m, r, recolor(part, tint), r.Min, draw.Src)
+}
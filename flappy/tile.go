@@ -0,0 +1,31 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build darwin linux
+
+package flappy
+
+import "github.com/adg/game/sim"
+
+// topTex returns the texture drawn on top of ground tile i, if any.
+func (g *Game) topTex(i int) (x int, ok bool) {
+	switch g.GroundType[i] {
+	case sim.TileSpring:
+		return sim.TexSpring, true
+	case sim.TileSpikes:
+		return sim.TexSpikes, true
+	}
+	return 0, false
+}
+
+// groundTexAt returns the texture of the surface of ground tile i.
+func (g *Game) groundTexAt(i int) int {
+	switch g.GroundType[i] {
+	case sim.TileLava:
+		return sim.TexLava
+	case sim.TileIce:
+		return sim.TexIce
+	}
+	return g.tex(i, g.GroundTex[i])
+}
@@ -0,0 +1,57 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build darwin linux
+
+package flappy
+
+import (
+	"log"
+
+	"github.com/adg/game/sim"
+	"golang.org/x/mobile/exp/sensor"
+)
+
+// tilting follows the accelerometer while the tilt controls are used.
+var tilting struct {
+	on         bool    // is the accelerometer on?
+	calibrated bool    // has the level been read since it was turned on?
+	level      float64 // the reading of a device held level
+}
+
+// updateTilt turns the accelerometer on while a run is played
+// with the tilt controls, and off otherwise.
+func updateTilt() {
+	want := screen == screenGame && game.Settings().Controls == sim.ControlsTilt
+	if want == tilting.on {
+		return
+	}
+	if want {
+		if err := sensor.Enable(sensor.Accelerometer, sim.TiltDelay); err != nil {
+			log.Print(err)
+			return
+		}
+		// However the player holds the device now is level.
+		tilting.calibrated = false
+	} else {
+		if err := sensor.Disable(sensor.Accelerometer); err != nil {
+			log.Print(err)
+		}
+		game.SetTilt(0)
+	}
+	tilting.on = want
+}
+
+// onSensor tilts the game by the accelerometer reading e.
+func onSensor(e sensor.Event) {
+	if !tilting.on || e.Sensor != sensor.Accelerometer || len(e.Data) < 3 {
+		return
+	}
+	z := e.Data[2] // along the axis out of the screen
+	if !tilting.calibrated {
+		tilting.level = z
+		tilting.calibrated = true
+	}
+	game.SetTilt(float32((z - tilting.level) / sim.TiltRange))
+}
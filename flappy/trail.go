@@ -0,0 +1,133 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build darwin linux
+
+package flappy
+
+import (
+	"image"
+	"image/draw"
+	"log"
+
+	"github.com/adg/game/sim"
+	"golang.org/x/mobile/exp/f32"
+	"golang.org/x/mobile/exp/sprite"
+	"golang.org/x/mobile/exp/sprite/clock"
+)
+
+const (
+	trailV      = 180  // scroll velocity above which the gopher leaves a trail
+	trailLength = 4    // ghosts in the trail
+	trailEvery  = 3    // frames between ghosts
+	trailAlpha  = 0.45 // opacity of the nearest ghost; the rest fade away
+)
+
+// trailSets is the first of the ghostly gopher textures,
+// which follow the star's tints.
+const trailSets = starSets + len(starTints)*sim.GopherSetSize
+
+// loadTrailSets loads a set of ghostly, pale blue gopher textures
+// for each ghost in the trail, each fainter than the last.
+func loadTrailSets(eng sprite.Engine, m image.Image, texs []sprite.SubTex) []sprite.SubTex {
+	r := texs[sim.TexGopherRun1].R
+	for x := sim.TexGopherRun1; x <= sim.TexGopherGlide; x++ {
+		r = r.Union(texs[x].R)
+	}
+	gopher := image.NewRGBA(image.Rect(0, 0, r.Dx(), r.Dy()))
+	draw.Draw(gopher, gopher.Bounds(), m, r.Min, draw.Src)
+
+	var sets []sprite.SubTex
+	for i := 0; i < trailLength; i++ {
+		t, err := eng.LoadTexture(ghost(gopher, trailAlpha*float32(trailLength-i)/trailLength))
+		if err != nil {
+			log.Fatal(err)
+		}
+		for x := sim.TexGopherRun1; x <= sim.TexGopherGlide; x++ {
+			sets = append(sets, sprite.SubTex{t, texs[x].R.Sub(r.Min)})
+		}
+	}
+	return sets
+}
+
+// ghost returns a pale blue silhouette of m with the given opacity.
+func ghost(m *image.RGBA, alpha float32) image.Image {
+	d := image.NewRGBA(m.Bounds())
+	for i := 0; i < len(m.Pix); i += 4 {
+		a := float32(m.Pix[i+3]) * alpha
+		d.Pix[i+0] = uint8(a * 0.8)
+		d.Pix[i+1] = uint8(a * 0.9)
+		d.Pix[i+2] = uint8(a)
+		d.Pix[i+3] = uint8(a)
+	}
+	return d
+}
+
+// trailTex returns ghost i's variant of gopher texture x.
+func trailTex(x, i int) int {
+	return trailSets + i*sim.GopherSetSize + x - sim.TexGopherRun1
+}
+
+// A trailPoint is where the gopher was drawn.
+type trailPoint struct {
+	a        f32.Affine // the gopher's transform
+	tex      int        // the gopher's texture, before it is tinted
+	distance float32    // how far the gopher had run
+}
+
+// A trail remembers where the gopher was drawn over the last few frames.
+type trail struct {
+	points [trailLength * trailEvery]trailPoint // a ring of the points recorded
+	next   int                                  // index in points of the next to be recorded
+	n      int                                  // number of points recorded
+	last   clock.Time                           // when the last point was recorded
+}
+
+// record notes that the gopher was drawn with a and texture x at t.
+func (tr *trail) record(t clock.Time, a f32.Affine, x int, distance float32) {
+	if tr.n > 0 && t == tr.last {
+		return
+	}
+	if t < tr.last {
+		// The clock was reset for a new run.
+		tr.n = 0
+	}
+	tr.last = t
+	tr.points[tr.next] = trailPoint{a, x, distance}
+	tr.next = (tr.next + 1) % len(tr.points)
+	if tr.n < len(tr.points) {
+		tr.n++
+	}
+}
+
+// ghost returns the point for ghost i, if there is one.
+func (tr *trail) ghost(i int) (trailPoint, bool) {
+	back := (i + 1) * trailEvery
+	if back > tr.n {
+		return trailPoint{}, false
+	}
+	n := len(tr.points)
+	return tr.points[(tr.next-back+n)%n], true
+}
+
+// addTrail adds nodes to parent that draw the ghosts in tr,
+// while the gopher is running fast.
+func (g *Game) addTrail(eng sprite.Engine, parent *sprite.Node, texs []sprite.SubTex, tr *trail) {
+	for i := trailLength - 1; i >= 0; i-- {
+		i := i
+		n := &sprite.Node{Arranger: arrangerFunc(func(eng sprite.Engine, n *sprite.Node, t clock.Time) {
+			p, ok := tr.ghost(i)
+			if !ok || g.ScrollV() < trailV || g.Over() || g.Choosing() {
+				eng.SetSubTex(n, sprite.SubTex{})
+				return
+			}
+			// The ghosts are left behind as the world scrolls.
+			p.a[0][2] -= g.Travelled - p.distance
+			eng.SetSubTex(n, texs[trailTex(p.tex, i)])
+			eng.SetTransform(n, p.a)
+		})}
+		eng.Register(n)
+		parent.AppendChild(n)
+	}
+}
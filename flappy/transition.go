@@ -0,0 +1,40 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build darwin linux
+
+package flappy
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/adg/game/sim"
+	"golang.org/x/mobile/exp/sprite/clock"
+)
+
+// fadeAlpha returns how dark the scene is at t, from 0 to 1.
+func (g *Game) fadeAlpha(t clock.Time) float32 {
+	if !g.Fade.Active {
+		return 0
+	}
+	dt := t - g.Fade.Start
+	switch {
+	case dt < 0:
+		return 0
+	case dt < sim.FadeTime:
+		return float32(dt) / sim.FadeTime
+	case dt < sim.FadeTime*2:
+		return 1 - float32(dt-sim.FadeTime)/sim.FadeTime
+	}
+	return 0
+}
+
+// paintFade paints black from transparent on the left to opaque on the right.
+func paintFade(m *image.RGBA, r image.Rectangle) {
+	for x := r.Min.X; x < r.Max.X; x++ {
+		a := uint8(0xff * (x - r.Min.X) / (r.Dx() - 1))
+		fillRect(m, image.Rect(x, r.Min.Y, x+1, r.Max.Y), color.RGBA{0, 0, 0, a})
+	}
+}
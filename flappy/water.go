@@ -0,0 +1,41 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build darwin linux
+
+package flappy
+
+import (
+	"github.com/adg/game/sim"
+	"golang.org/x/mobile/exp/f32"
+	"golang.org/x/mobile/exp/sprite"
+	"golang.org/x/mobile/exp/sprite/clock"
+)
+
+// addWater adds a node to parent that draws the water in the pools,
+// in front of the gopher swimming through it.
+func (g *Game) addWater(eng sprite.Engine, parent *sprite.Node, texs []sprite.SubTex) {
+	var parts [sim.WorldTiles]*sprite.Node
+	var vis [sim.WorldTiles]visibility
+	water := &sprite.Node{Arranger: arrangerFunc(func(eng sprite.Engine, _ *sprite.Node, t clock.Time) {
+		for i := range vis {
+			x := float32(i)*sim.TileWidth - g.drawScroll()
+			if i >= g.Tiles || g.GroundType[i] != sim.TileWater || g.view.offScreen(x, x+sim.TileWidth) {
+				vis[i].hide(eng, parts[i])
+				continue
+			}
+			vis[i].show(eng, parts[i], texs[sim.TexWater], f32.Affine{
+				{sim.TileWidth, 0, x},
+				{0, sim.PoolDepth, g.WaterY(i)},
+			})
+		}
+	})}
+	eng.Register(water)
+	parent.AppendChild(water)
+	for i := range parts {
+		parts[i] = &sprite.Node{}
+		eng.Register(parts[i])
+		water.AppendChild(parts[i])
+	}
+}
@@ -0,0 +1,184 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build darwin linux
+
+package flappy
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"golang.org/x/mobile/asset"
+	"golang.org/x/mobile/exp/audio/al"
+)
+
+// A pcmStream is a stream of 16-bit PCM samples, read a buffer at a time
+// so that long tracks needn't be held in memory.
+type pcmStream interface {
+	io.ReadCloser
+	format() (uint32, int32) // the OpenAL format and sample rate of the samples
+	rewind() error           // starts the stream again from the beginning
+}
+
+// A wavStream streams the samples of a WAV asset,
+// decoding them first if they are IMA ADPCM.
+type wavStream struct {
+	f        asset.File
+	fmt      uint32 // OpenAL format
+	rate     int32  // samples per second
+	offset   int64  // where the samples start in the file
+	size     int64  // length of the samples, in bytes
+	left     int64  // bytes of samples still to be read
+	channels int    // number of channels
+	block    int    // length of each IMA ADPCM block, or 0 for PCM
+	raw      []byte // the IMA ADPCM block being decoded
+	pcm      []byte // decoded samples not yet read
+}
+
+// openWAV opens the WAV asset called name, which must hold
+// 16-bit PCM or IMA ADPCM samples, in mono or stereo.
+func openWAV(name string) (*wavStream, error) {
+	f, err := asset.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	s, err := readWAVHeader(f)
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("%s: %v", name, err)
+	}
+	return s, nil
+}
+
+// readWAVHeader reads the chunks of f up to the samples.
+func readWAVHeader(f asset.File) (*wavStream, error) {
+	var riff [12]byte
+	if _, err := io.ReadFull(f, riff[:]); err != nil {
+		return nil, err
+	}
+	if string(riff[0:4]) != "RIFF" || string(riff[8:12]) != "WAVE" {
+		return nil, errors.New("not a WAV file")
+	}
+	s := &wavStream{f: f}
+	for {
+		var hdr [8]byte
+		if _, err := io.ReadFull(f, hdr[:]); err != nil {
+			return nil, err
+		}
+		size := int64(binary.LittleEndian.Uint32(hdr[4:]))
+		switch string(hdr[0:4]) {
+		case "fmt ":
+			var c struct {
+				Format, Channels uint16
+				Rate, ByteRate   uint32
+				Align, Bits      uint16
+			}
+			if err := binary.Read(f, binary.LittleEndian, &c); err != nil {
+				return nil, err
+			}
+			switch {
+			case c.Format == wavADPCM && c.Bits == 4:
+				s.block = int(c.Align)
+			case c.Format != 1 || c.Bits != 16:
+				return nil, errors.New("not 16-bit PCM or IMA ADPCM")
+			}
+			switch {
+			case c.Channels == 1:
+				s.fmt = al.FormatMono16
+			case c.Channels == 2:
+				s.fmt = al.FormatStereo16
+			default:
+				return nil, fmt.Errorf("%d channels", c.Channels)
+			}
+			s.rate = int32(c.Rate)
+			s.channels = int(c.Channels)
+			if _, err := f.Seek(size-16, io.SeekCurrent); err != nil {
+				return nil, err
+			}
+		case "data":
+			if s.fmt == 0 {
+				return nil, errors.New("samples before format")
+			}
+			off, err := f.Seek(0, io.SeekCurrent)
+			if err != nil {
+				return nil, err
+			}
+			s.offset, s.size, s.left = off, size, size
+			return s, nil
+		default:
+			// Chunks are padded to an even length.
+			if _, err := f.Seek(size+size&1, io.SeekCurrent); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+func (s *wavStream) Read(p []byte) (int, error) {
+	if s.block != 0 {
+		return s.readADPCM(p)
+	}
+	if s.left == 0 {
+		return 0, io.EOF
+	}
+	if int64(len(p)) > s.left {
+		p = p[:s.left]
+	}
+	n, err := s.f.Read(p)
+	s.left -= int64(n)
+	if err == io.EOF && s.left > 0 {
+		err = io.ErrUnexpectedEOF
+	}
+	return n, err
+}
+
+// readADPCM reads the samples decoded from the IMA ADPCM blocks into p.
+func (s *wavStream) readADPCM(p []byte) (int, error) {
+	for len(s.pcm) == 0 {
+		if s.left == 0 {
+			return 0, io.EOF
+		}
+		n := int64(s.block)
+		if n > s.left {
+			n = s.left
+		}
+		if int64(cap(s.raw)) < n {
+			s.raw = make([]byte, n)
+		}
+		s.raw = s.raw[:n]
+		if _, err := io.ReadFull(s.f, s.raw); err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return 0, err
+		}
+		s.left -= n
+		pcm, err := decodeADPCM(s.pcm[:0], s.raw, s.channels)
+		if err != nil {
+			return 0, err
+		}
+		s.pcm = pcm
+	}
+	n := copy(p, s.pcm)
+	s.pcm = s.pcm[n:]
+	return n, nil
+}
+
+func (s *wavStream) Close() error {
+	return s.f.Close()
+}
+
+func (s *wavStream) format() (uint32, int32) {
+	return s.fmt, s.rate
+}
+
+func (s *wavStream) rewind() error {
+	s.left = s.size
+	s.pcm = s.pcm[:0]
+	_, err := s.f.Seek(s.offset, io.SeekStart)
+	return err
+}
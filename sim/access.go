@@ -0,0 +1,37 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sim
+
+// A style is how the textures are painted: in which theme,
+// and for which of the accessibility modes.
+// The modes change the textures rather than the scenes,
+// so that they apply to everything that is drawn.
+type Style struct {
+	Theme        *Theme
+	HighContrast bool // outline the ground and mute the sky
+	Colorblind   bool // stripe the hazards, rather than rely on their colour
+}
+
+// Style returns how the player has chosen the textures be painted.
+func (g *Game) Style() Style {
+	return Style{
+		Theme:        g.theme(),
+		HighContrast: g.settings.HighContrast,
+		Colorblind:   g.settings.Colorblind,
+	}
+}
+
+// restyle tells the game's restyler if the style has changed from old,
+// so that the player sees the change straight away.
+func (g *Game) restyle(old Style) {
+	if g.Style() != old && g.restyler != nil {
+		g.restyler()
+	}
+}
+
+// OnRestyle calls f whenever the player changes how the textures are painted.
+func (g *Game) OnRestyle(f func()) {
+	g.restyler = f
+}
@@ -0,0 +1,72 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sim
+
+const ToastTime = ClockRate * 3 // how long an unlocked achievement is announced for
+
+// An achievement is a lifetime milestone.
+type achievement struct {
+	id     string    // saved identifier
+	name   string    // what the player is told
+	counts gameEvent // event that counts towards the goal
+	goal   int       // how many events unlock the achievement
+}
+
+var achievements = []achievement{
+	{"first-death", "Bitten the dust", eventDeath, 1},
+	{"marathon", "Marathon gopher", eventMetre, 10000},
+	{"flapper", "Frequent flapper", eventFlap, 100},
+	{"hoarder", "Coin hoarder", eventCoin, 1000},
+}
+
+// An Achievement is a lifetime milestone the player may unlock.
+type Achievement struct {
+	Name     string // what the player is told
+	Unlocked bool   // has the player reached the goal?
+	Progress int    // how far the player has got
+	Goal     int    // how far the player must get
+}
+
+// Achievements returns every achievement and the player's progress towards it.
+func (g *Game) Achievements() []Achievement {
+	var as []Achievement
+	for _, a := range achievements {
+		n := g.saved.Totals[a.counts]
+		if n > a.goal {
+			n = a.goal
+		}
+		as = append(as, Achievement{a.name, g.unlocked(a.id), n, a.goal})
+	}
+	return as
+}
+
+func (g *Game) unlocked(id string) bool {
+	for _, u := range g.saved.Unlocked {
+		if u == id {
+			return true
+		}
+	}
+	return false
+}
+
+// countAchievements counts e towards the lifetime totals,
+// unlocking and announcing any achievements that are reached,
+// and reports whether any were.
+func (g *Game) countAchievements(e gameEvent) bool {
+	if g.saved.Totals == nil {
+		g.saved.Totals = make(map[gameEvent]int)
+	}
+	g.saved.Totals[e]++
+	unlocked := false
+	for _, a := range achievements {
+		if a.counts == e && g.saved.Totals[e] >= a.goal && !g.unlocked(a.id) {
+			g.saved.Unlocked = append(g.saved.Unlocked, a.id)
+			g.Toast = a.name
+			g.ToastTime = g.LastCalc
+			unlocked = true
+		}
+	}
+	return unlocked
+}
@@ -0,0 +1,61 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sim
+
+const (
+	maxAcorns     = 3             // maximum number of acorns in flight at once
+	acornSize     = TileWidth / 2 // width and height of an acorn
+	acornV        = 240           // horizontal velocity of a thrown acorn, relative to the ground
+	acornThrowV   = -60           // initial vertical velocity of a thrown acorn
+	acornGravity  = 0.25          // gravity on acorns, as a fraction of the gopher's, so they fly further
+	acornCooldown = 30            // how long after a throw before the gopher may throw again
+)
+
+// Throw makes the gopher throw an acorn ahead of it,
+// which knocks out the first enemy or obstacle it hits.
+func (g *Game) Throw(down bool) {
+	if !g.Choosing() && !g.Paused() && !g.takeInput(inputThrow, down, 0) {
+		return
+	}
+	if !down || g.Over() || g.Paused() || g.countEntities(EntityAcorn) >= maxAcorns || g.LastCalc < g.thrown+acornCooldown {
+		return
+	}
+	_, y0, x1, _ := g.GopherBounds()
+	g.addEntity(newAcorn(x1, y0, acornGravity*g.physics.Gravity))
+	g.thrown = g.LastCalc
+}
+
+// newAcorn returns an acorn thrown from x and y, falling with gravity.
+func newAcorn(x, y, gravity float32) Entity {
+	return Entity{
+		Kind:       EntityAcorn,
+		has:        hasVelocity | hasCollider | hasRenderable | isBrittle,
+		position:   position{x, y, acornSize, acornSize},
+		velocity:   velocity{acornV, acornThrowV, gravity},
+		collider:   collider{touches: touchFoes},
+		renderable: renderable{Tex: TexAcorn, Layer: LayerAcorns, Tumble: true},
+	}
+}
+
+// knockOut knocks out the first enemy or obstacle that a touched
+// as it moved this step, and reports whether it hit anything.
+func (g *Game) knockOut(a *Entity) bool {
+	dx, dy := a.vx*g.dt(), a.vy*g.dt()
+	b := a.box().moved(-dx, -dy)
+	for i := range g.Entities {
+		e := &g.Entities[i]
+		if e.has&hasCollider == 0 || e.touches != touchHarm || !b.sweep(e.box(), dx, dy) {
+			continue
+		}
+		if e.Kind == EntityEnemy {
+			knockOutEnemy(e)
+		} else {
+			removeEntity(e)
+		}
+		g.soundAt(SfxHit, a.X)
+		return true
+	}
+	return false
+}
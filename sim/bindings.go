@@ -0,0 +1,78 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sim
+
+// Actions the player's keys, gamepad buttons, touches, and gestures do.
+const (
+	ActionJump       = iota // jump and flap, and choose
+	ActionSlide             // slide along the ground
+	ActionThrow             // throw an acorn
+	ActionDash              // dash ahead
+	ActionNext              // show the next character, item, or option
+	ActionPrevious          // show the previous character, item, or option
+	ActionBack              // leave the shop or the character select screen
+	ActionPause             // pause and resume a run
+	ActionShop              // go in and out of the shop
+	ActionMode              // change the game mode
+	ActionDaily             // switch the daily challenge on and off
+	ActionRefund            // refund the item shown in the shop
+	ActionDebug             // show and hide the debug overlay
+	ActionScreenshot        // save a screenshot
+	NumActions
+)
+
+// ActionNames name the actions in the saved settings.
+var ActionNames = [NumActions]string{
+	"jump", "slide", "throw", "dash", "next", "previous", "back",
+	"pause", "shop", "mode", "daily", "refund", "debug", "screenshot",
+}
+
+// A Binding lists the inputs that do an action.
+type Binding struct {
+	Keys     []string // key names; see keyCodes
+	Buttons  []string // gamepad buttons; see padNames
+	Gestures []string // gestures of the swipe controls; see gestureNames
+}
+
+// defaultBindings are the inputs for each action
+// that the player hasn't rebound.
+var defaultBindings = [NumActions]Binding{
+	ActionJump:       {Keys: []string{"Space", "Up"}, Buttons: []string{"A"}, Gestures: []string{"tap", "hold", "swipe-up"}},
+	ActionSlide:      {Keys: []string{"Down"}, Buttons: []string{"B"}, Gestures: []string{"swipe-down"}},
+	ActionThrow:      {Keys: []string{"X"}, Buttons: []string{"X"}, Gestures: []string{"swipe-back"}},
+	ActionDash:       {Keys: []string{"Right"}, Buttons: []string{"Right"}, Gestures: []string{"swipe-forward"}},
+	ActionNext:       {Keys: []string{"Right"}, Buttons: []string{"Right"}},
+	ActionPrevious:   {Keys: []string{"Left"}, Buttons: []string{"Left"}},
+	ActionBack:       {Keys: []string{"Escape"}, Buttons: []string{"B"}},
+	ActionPause:      {Keys: []string{"Escape", "P"}, Buttons: []string{"Start"}},
+	ActionShop:       {Keys: []string{"S"}},
+	ActionMode:       {Keys: []string{"M"}},
+	ActionDaily:      {Keys: []string{"D"}},
+	ActionRefund:     {Keys: []string{"R"}},
+	ActionDebug:      {Keys: []string{"F3"}},
+	ActionScreenshot: {Keys: []string{"F12"}},
+}
+
+// Binding returns the inputs for action a.
+func (s Settings) Binding(a int) Binding {
+	if b, ok := s.Bindings[ActionNames[a]]; ok {
+		return b
+	}
+	return defaultBindings[a]
+}
+
+// RebindKey returns s with the key named name doing action a
+// instead of the keys that did it before.
+func (s Settings) RebindKey(a int, name string) Settings {
+	b := s.Binding(a)
+	b.Keys = []string{name}
+	m := make(map[string]Binding, len(s.Bindings)+1)
+	for k, v := range s.Bindings {
+		m[k] = v
+	}
+	m[ActionNames[a]] = b
+	s.Bindings = m
+	return s
+}
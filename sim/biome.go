@@ -0,0 +1,80 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sim
+
+const biomeLength = 400 // distance the gopher runs through each biome
+
+// A Biome is a stretch of the world with its own terrain and look.
+type Biome struct {
+	groundChangeProb int     // 1/probability of ground height change, or 0 to use the difficulty's
+	groundWobbleProb int     // 1/probability of minor ground height change, or 0 to use the difficulty's
+	groundMin        float32 // highest ground y-offset
+	groundMax        float32 // lowest ground y-offset
+	obstacles        []int   // kinds of obstacle that appear; see obstacleRock and friends
+	scenery          []int   // kinds of scenery that appear; see sceneryTree and friends
+
+	// Tint recolours the ground textures.
+	// It is given and returns premultiplied red, green, and blue.
+	Tint func(r, g, b float32) (float32, float32, float32)
+}
+
+// Biomes, in the order in which the gopher runs through them.
+const (
+	biomeMeadow = iota
+	biomeMountains
+	biomeDesert
+	biomeCave
+	NumBiomes
+)
+
+var Biomes = [NumBiomes]Biome{
+	biomeMeadow: {
+		groundMin: groundMin,
+		groundMax: groundMax,
+		obstacles: []int{obstacleRock, obstacleLog, obstaclePipe, obstacleLowPipe},
+		scenery:   []int{sceneryTree, sceneryFlowers, sceneryFence, sceneryPebbles},
+		Tint:      func(r, g, b float32) (float32, float32, float32) { return r, g, b },
+	},
+	biomeMountains: {
+		groundChangeProb: 2,
+		groundMin:        TileHeight * (TilesY - 3*TilesY/5),
+		groundMax:        groundMax,
+		obstacles:        []int{obstacleRock, obstacleLog},
+		scenery:          []int{sceneryTree, sceneryPebbles},
+		Tint: func(r, g, b float32) (float32, float32, float32) {
+			// Grey, rocky slopes.
+			l := (r + g + b) / 3
+			return l, l, l * 1.1
+		},
+	},
+	biomeDesert: {
+		groundChangeProb: 8,
+		groundWobbleProb: 2,
+		groundMin:        TileHeight * (TilesY - TilesY/4),
+		groundMax:        groundMax,
+		obstacles:        []int{obstacleRock, obstaclePipe},
+		scenery:          []int{sceneryFence, sceneryPebbles},
+		Tint: func(r, g, b float32) (float32, float32, float32) {
+			// Sandy dunes.
+			return r*0.4 + g*1.2, g*1.1 + r*0.2, b * 0.6
+		},
+	},
+	biomeCave: {
+		groundMin: groundMin,
+		groundMax: groundMax,
+		obstacles: []int{obstacleRock, obstacleLowPipe},
+		scenery:   []int{sceneryPebbles},
+		Tint: func(r, g, b float32) (float32, float32, float32) {
+			// Damp, purplish rock.
+			l := (r + g + b) / 3
+			return l * 0.7, l * 0.55, l * 0.8
+		},
+	},
+}
+
+// biomeAt returns the biome at the given distance.
+func biomeAt(distance int) int {
+	return distance / biomeLength % NumBiomes
+}
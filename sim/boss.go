@@ -0,0 +1,150 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sim
+
+import (
+	"math"
+
+	"golang.org/x/mobile/exp/sprite/clock"
+)
+
+const (
+	bossEvery       = 1000           // distance between boss encounters
+	BossSize        = TileWidth * 3  // width and height of the boss
+	bossReach       = TileWidth * 2  // how far past its resting place the boss lunges
+	bossIntroTime   = 120            // how long the boss takes to appear
+	bossFightTime   = ClockRate * 20 // how long the gopher must survive the boss
+	bossLungeTime   = 90             // how long a lunge lasts
+	bossAttackEvery = 150            // time between attacks
+	bossDigAhead    = GopherTile + 8 // ground tile on which dug up rocks land
+	bossReward      = 100            // coins awarded for surviving the boss
+	bossRetreatV    = 60             // velocity with which the defeated boss retreats
+
+	// The boss gives chase a little way behind the gopher,
+	// so that it only catches the gopher when it lunges.
+	bossGap   = TileWidth / 2                             // gap between the resting boss and the gopher
+	bossRestX = GopherTile*TileWidth - BossSize - bossGap // x-offset of the boss while it gives chase
+)
+
+// Boss states.
+const (
+	bossAsleep   = iota // waiting for the gopher to reach the next encounter
+	bossIntro           // emerging from the left of the screen
+	bossChase           // following the gopher, between attacks
+	bossLunge           // lunging at the gopher
+	bossDefeated        // retreating off screen
+)
+
+// A boss is a giant mole that chases the gopher
+// every bossEvery distance units.
+type boss struct {
+	state  int        // see bossAsleep and friends
+	since  clock.Time // when the boss entered its state
+	X      float32    // x-offset of the boss, on screen
+	next   int        // distance of the next encounter
+	end    clock.Time // when the gopher will have survived the encounter
+	attack clock.Time // when the boss next attacks
+}
+
+// BossActive reports whether a boss encounter is under way,
+// during which the terrain is flat and nothing else is spawned.
+func (g *Game) BossActive() bool {
+	return g.boss.state != bossAsleep
+}
+
+func (g *Game) setBossState(state int) {
+	g.boss.state = state
+	g.boss.since = g.LastCalc
+}
+
+// calcBoss runs the boss state machine.
+func (g *Game) calcBoss() {
+	b := &g.boss
+	dt := g.LastCalc - b.since
+	switch b.state {
+	case bossAsleep:
+		if !g.Over() && g.Distance() >= b.next {
+			b.X = -BossSize
+			g.setBossState(bossIntro)
+		}
+	case bossIntro:
+		b.X = -BossSize + (bossRestX+BossSize)*float32(dt)/bossIntroTime
+		if dt >= bossIntroTime {
+			b.end = g.LastCalc + bossFightTime
+			b.attack = g.LastCalc + bossAttackEvery
+			g.setBossState(bossChase)
+		}
+	case bossChase:
+		b.X = bossRestX
+		switch {
+		case g.Over():
+			g.setBossState(bossDefeated)
+		case g.LastCalc >= b.end:
+			// Gopher survived.
+			g.Collected += bossReward
+			g.setBossState(bossDefeated)
+		case g.LastCalc >= b.attack:
+			b.attack = g.LastCalc + bossAttackEvery
+			if g.rng.Intn(2) == 0 {
+				g.setBossState(bossLunge)
+			} else {
+				g.bossDig()
+			}
+		}
+	case bossLunge:
+		f := float64(dt) / bossLungeTime
+		b.X = bossRestX + bossReach*float32(math.Sin(math.Pi*f))
+		if dt >= bossLungeTime {
+			g.setBossState(bossChase)
+		}
+	case bossDefeated:
+		b.X -= bossRetreatV * g.dt()
+		if b.X < -BossSize {
+			b.next += bossEvery
+			g.setBossState(bossAsleep)
+		}
+	}
+
+	if g.BossActive() && !g.Over() && g.bossHitGopher() {
+		g.killGopher(causeBoss)
+	}
+}
+
+// bossDig makes the boss throw up a rock in front of the gopher.
+func (g *Game) bossDig() {
+	i := bossDigAhead
+	g.addEntity(newObstacle(float32(i*TileWidth), g.GroundY[i], rockW, rockH, 0, TexRock))
+}
+
+// BossX returns the x-offset of the boss, on screen.
+func (g *Game) BossX() float32 {
+	return g.boss.X
+}
+
+// BossY returns the y-offset of the top of the boss.
+func (g *Game) BossY() float32 {
+	return g.GroundY[GopherTile] - BossSize
+}
+
+// bossBox returns the bounding box of the boss.
+func (g *Game) bossBox() box {
+	x, y := g.boss.X+g.Scroll.X, g.BossY()
+	return box{x, y, x + BossSize, y + BossSize}
+}
+
+// bossHitGopher reports whether the boss has caught the gopher.
+// Only a lunge reaches the gopher; the resting boss stays behind it.
+func (g *Game) bossHitGopher() bool {
+	grace := g.physics.ClimbGrace // how far the gopher may overlap the boss
+	return g.bossBox().inset(grace).overlaps(g.gopherBox())
+}
+
+// BossTimeLeft returns how many seconds the gopher must survive the boss.
+func (g *Game) BossTimeLeft() int {
+	if g.boss.state != bossChase && g.boss.state != bossLunge {
+		return 0
+	}
+	return int(g.boss.end-g.LastCalc)/ClockRate + 1
+}
@@ -0,0 +1,105 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sim
+
+import "strconv"
+
+// A Character is someone the player may run as.
+type Character struct {
+	id    string  // saved identifier
+	Name  string  // what the player is told
+	jump  float32 // jump velocity, as a multiple of the usual one
+	flaps int     // number of flaps allowed in mid-air
+	cost  int     // coins it takes to unlock the character
+
+	// Tint recolours the gopher textures for the character,
+	// or is nil to use them as they are.
+	Tint func(r, g, b float32) (float32, float32, float32)
+}
+
+var Characters = []Character{
+	{id: "gopher", Name: "Gopher", jump: 1, flaps: initMaxFlaps},
+	{
+		id: "hopper", Name: "Hopper", jump: 1.15, flaps: 0, cost: 200,
+		Tint: func(r, g, b float32) (float32, float32, float32) { return g * 0.6, g * 1.2, b * 0.5 },
+	},
+	{
+		id: "flutter", Name: "Flutter", jump: 0.9, flaps: 3, cost: 300,
+		Tint: func(r, g, b float32) (float32, float32, float32) { return b * 1.2, r * 0.7, g * 0.9 },
+	},
+	{
+		id: "goldie", Name: "Goldie", jump: 1, flaps: 2, cost: 1000,
+		Tint: func(r, g, b float32) (float32, float32, float32) { return r * 1.4, g * 1.2, b * 0.3 },
+	},
+}
+
+// Choosing reports whether the player is choosing a character.
+func (g *Game) Choosing() bool {
+	return g.state == StateMenu || g.state == StateShop
+}
+
+// Cycle shows the player the character, or the item in the shop,
+// d places along.
+func (g *Game) Cycle(d int) {
+	switch g.state {
+	case StatePaused:
+		g.pauseItem = ((g.pauseItem+d)%numPauseOptions + numPauseOptions) % numPauseOptions
+	case StateResults:
+		g.summaryItem = ((g.summaryItem+d)%numSummaryOptions + numSummaryOptions) % numSummaryOptions
+	case StateShop:
+		n := len(shopItems())
+		g.ShopItem = ((g.ShopItem+d)%n + n) % n
+		g.shopMsg = ""
+	case StateMenu:
+		n := len(Characters)
+		g.Character = ((g.Character+d)%n + n) % n
+	}
+}
+
+// chooseCharacter starts a run as the character being shown,
+// unlocking it first if it is locked and the player can afford it.
+func (g *Game) chooseCharacter() {
+	c := &Characters[g.Character]
+	if !g.characterUnlocked(g.Character) && g.Buy("character:"+c.id) != nil {
+		return
+	}
+	g.saved.Character = c.id
+	g.saveProgress()
+	g.transitionTo(func() {
+		g.setState(StatePlaying)
+		g.reset()
+	})
+}
+
+func (g *Game) characterUnlocked(i int) bool {
+	if Characters[i].cost == 0 {
+		return true
+	}
+	for _, id := range g.saved.Characters {
+		if id == Characters[i].id {
+			return true
+		}
+	}
+	return false
+}
+
+// chosenCharacter returns the index of the character last chosen.
+func (g *Game) chosenCharacter() int {
+	for i, c := range Characters {
+		if c.id == g.saved.Character && g.characterUnlocked(i) {
+			return i
+		}
+	}
+	return 0
+}
+
+// CharacterPrompt returns what the character select screen
+// tells the player about the character being shown.
+func (g *Game) CharacterPrompt() string {
+	if g.characterUnlocked(g.Character) {
+		return "PRESS TO PLAY"
+	}
+	return "UNLOCK " + strconv.Itoa(Characters[g.Character].cost) + " COINS"
+}
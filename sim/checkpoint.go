@@ -0,0 +1,60 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sim
+
+const (
+	checkpointEvery = 250 // distance between checkpoints
+	ContinueCost    = 50  // coins it costs to continue from a checkpoint
+	continueDelay   = 30  // how long after dying before the player may continue
+)
+
+// A checkpoint is a point in the run that the player may continue from.
+type checkpoint struct {
+	distance int     // distance at the checkpoint
+	points   float32 // points scored by the checkpoint
+	scrollV  float32 // scroll velocity at the checkpoint
+}
+
+// calcCheckpoint records the latest checkpoint the gopher has passed.
+func (g *Game) calcCheckpoint() {
+	if g.Over() {
+		return
+	}
+	if d := g.Distance() / checkpointEvery * checkpointEvery; d > g.checkpoint.distance {
+		g.checkpoint = checkpoint{d, g.points, g.Scroll.V}
+	}
+}
+
+// CanContinue reports whether the dead gopher may continue from a checkpoint.
+// Timed runs can't be continued part way; see timed.
+func (g *Game) CanContinue() bool {
+	return g.Over() && !g.Finished() && !g.replaying && !g.timed() &&
+		g.checkpoint.distance > 0 &&
+		(g.saved.Continues > 0 || g.saved.Coins >= ContinueCost) &&
+		g.LastCalc-g.Gopher.DeadTime > continueDelay
+}
+
+// continueRun pays for and restarts the run from the last checkpoint,
+// using up a continue bought in the shop if the player has one.
+func (g *Game) continueRun() {
+	if g.saved.Continues > 0 {
+		g.saved.Continues--
+	} else {
+		g.saved.Coins -= ContinueCost
+	}
+	g.saveProgress()
+
+	c := g.checkpoint
+	nearMisses, xpPaid := g.nearMisses, g.xpPaid
+	g.reset()
+	g.recording.ok = false
+	g.nearMisses, g.xpPaid = nearMisses, xpPaid
+	g.checkpoint = c
+	g.Travelled = float32(c.distance) * TileWidth
+	g.points = c.points
+	g.Scroll.V = c.scrollV
+	g.Gopher.Lives = 1
+	g.boss.next = (c.distance/bossEvery + 1) * bossEvery
+}
@@ -0,0 +1,64 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sim
+
+const (
+	grabWindow = 30             // how long the gopher can hang on to a cliff
+	climbTime  = 15             // how long it takes to scramble up a cliff
+	maxGrab    = TileHeight * 4 // tallest cliff the gopher can grab on to
+)
+
+// Wall grab states.
+const (
+	grabNone     = iota // not holding on to a cliff
+	GrabHanging         // hanging on to the face of a cliff
+	GrabClimbing        // scrambling up a cliff
+)
+
+// canGrab reports whether the gopher can grab on to the cliff
+// it has run into, instead of crashing.
+func (g *Game) canGrab() bool {
+	if g.Invulnerable() || g.dashing() || g.Gopher.Starred {
+		// Gopher will be lifted onto the cliff anyway.
+		return false
+	}
+	return g.Gopher.Y+TileHeight-g.GroundY[GopherTile+1] <= maxGrab
+}
+
+// grabWall makes the gopher hang on to the cliff in front of it.
+func (g *Game) grabWall() {
+	g.Gopher.Grab = GrabHanging
+	g.Gopher.grabTime = g.LastCalc
+	g.Gopher.V = 0
+}
+
+// climbWall makes the hanging gopher scramble up the cliff.
+func (g *Game) climbWall() {
+	g.Gopher.Grab = GrabClimbing
+	g.Gopher.grabTime = g.LastCalc
+	g.Gopher.grabY = g.Gopher.Y
+}
+
+// calcGrab moves the gopher while it is on a cliff face.
+func (g *Game) calcGrab() {
+	dt := g.LastCalc - g.Gopher.grabTime
+	switch g.Gopher.Grab {
+	case GrabHanging:
+		if dt > grabWindow {
+			// Gopher lost its grip.
+			g.Gopher.Grab = grabNone
+			g.killGopher(causeCliff)
+		}
+	case GrabClimbing:
+		top := g.GroundY[GopherTile+1] - TileHeight
+		if dt >= climbTime {
+			g.Gopher.Grab = grabNone
+			g.Gopher.Y = top
+			g.event(eventClimb)
+			return
+		}
+		g.Gopher.Y = g.Gopher.grabY + (top-g.Gopher.grabY)*float32(dt)/climbTime
+	}
+}
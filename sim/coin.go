@@ -0,0 +1,70 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sim
+
+import "math"
+
+const (
+	maxCoins  = 8                 // maximum number of coins at once
+	coinProb  = 3                 // 1/probability of a new tile having a coin
+	coinSize  = TileWidth * 3 / 4 // width and height of a coin
+	coinMaxUp = 4                 // maximum height of a coin above the ground, in tiles
+
+	magnetRange = TileWidth * 5 // how close a coin must be to be drawn in by the magnet
+	magnetV     = 180           // how fast the magnet draws coins in
+)
+
+// spawnCoin maybe places a coin in the air above the last ground tile.
+func (g *Game) spawnCoin() {
+	if g.countEntities(EntityCoin) >= maxCoins || g.rng.Intn(coinProb) != 0 {
+		return
+	}
+	last := g.lastTile()
+	x := float32(last * TileWidth)
+	if o := g.lastEntity(EntityObstacle); o != nil && o.X == x {
+		// Don't put coins inside obstacles.
+		return
+	}
+	up := float32(1 + g.rng.Intn(coinMaxUp))
+	g.addEntity(newCoin(x+(TileWidth-coinSize)/2, g.surfaceY(last)-up*TileHeight))
+}
+
+// newCoin returns a coin floating at x and y.
+func newCoin(x, y float32) Entity {
+	return Entity{
+		Kind:       EntityCoin,
+		has:        hasCollider | hasRenderable,
+		position:   position{x, y, coinSize, coinSize},
+		collider:   collider{touches: touchCollect},
+		renderable: renderable{Tex: TexCoin, Layer: LayerItems},
+	}
+}
+
+// calcCoins draws coins towards the gopher while it is magnetised.
+func (g *Game) calcCoins() {
+	if !g.Magnetised || g.Over() {
+		return
+	}
+	x0, y0, x1, y1 := g.GopherBounds()
+	gx, gy := (x0+x1-coinSize)/2, (y0+y1-coinSize)/2
+	for i := range g.Entities {
+		c := &g.Entities[i]
+		if c.Kind != EntityCoin || c.has == 0 {
+			continue
+		}
+		dx, dy := gx-c.X, gy-c.Y
+		d := float32(math.Hypot(float64(dx), float64(dy)))
+		if d > magnetRange || d == 0 {
+			continue
+		}
+		step := magnetV * g.dt()
+		if d < step {
+			c.X, c.Y = gx, gy
+			continue
+		}
+		c.X += dx / d * step
+		c.Y += dy / d * step
+	}
+}
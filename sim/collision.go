@@ -0,0 +1,78 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sim
+
+import "math"
+
+// A box is an axis-aligned bounding box, relative to the ground tiles.
+type box struct {
+	x0, y0, x1, y1 float32
+}
+
+// overlaps reports whether b and c overlap.
+// Boxes that only touch don't overlap.
+func (b box) overlaps(c box) bool {
+	return b.across(c) && b.y0 < c.y1 && b.y1 > c.y0
+}
+
+// across reports whether b and c overlap horizontally.
+func (b box) across(c box) bool {
+	return b.x0 < c.x1 && b.x1 > c.x0
+}
+
+// inset returns b shrunk by d on each side.
+func (b box) inset(d float32) box {
+	return box{b.x0 + d, b.y0 + d, b.x1 - d, b.y1 - d}
+}
+
+// moved returns b moved by dx and dy.
+func (b box) moved(dx, dy float32) box {
+	return box{b.x0 + dx, b.y0 + dy, b.x1 + dx, b.y1 + dy}
+}
+
+// sweep reports whether b overlaps c at any point as it moves by dx and dy,
+// so that something moving fast can't pass straight through c.
+func (b box) sweep(c box, dx, dy float32) bool {
+	enterX, leaveX := sweepAxis(b.x0, b.x1, c.x0, c.x1, dx)
+	enterY, leaveY := sweepAxis(b.y0, b.y1, c.y0, c.y1, dy)
+	enter := float32(math.Max(float64(enterX), float64(enterY)))
+	leave := float32(math.Min(float64(leaveX), float64(leaveY)))
+	return enter < leave && enter < 1 && leave > 0
+}
+
+// sweepAxis returns when, as a fraction of the move, the span a0 to a1
+// moving by d starts and stops overlapping the span c0 to c1.
+func sweepAxis(a0, a1, c0, c1, d float32) (enter, leave float32) {
+	inf := float32(math.Inf(1))
+	if d == 0 {
+		if a0 < c1 && a1 > c0 {
+			return -inf, inf
+		}
+		return inf, -inf
+	}
+	enter, leave = (c0-a1)/d, (c1-a0)/d
+	if enter > leave {
+		enter, leave = leave, enter
+	}
+	return enter, leave
+}
+
+// gopherBox returns the gopher's bounding box.
+func (g *Game) gopherBox() box {
+	x0, y0, x1, y1 := g.GopherBounds()
+	return box{x0, y0, x1, y1}
+}
+
+// gopherHits reports whether the gopher touched c as it moved this step.
+func (g *Game) gopherHits(c box) bool {
+	dx, dy := g.Gopher.dx, g.Gopher.dy
+	return g.gopherBox().moved(-dx, -dy).sweep(c, dx, dy)
+}
+
+// groundBox returns the bounding box of ground tile i,
+// which is solid all the way down.
+func (g *Game) groundBox(i int) box {
+	return box{float32(i * TileWidth), g.GroundY[i], float32((i + 1) * TileWidth), math.MaxFloat32}
+}
@@ -0,0 +1,54 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sim
+
+import "golang.org/x/mobile/exp/sprite/clock"
+
+const (
+	comboWindow   = 20 // how long the gopher may stay on the ground between combo jumps
+	comboStep     = 3  // number of combo jumps needed to raise the multiplier
+	maxMultiplier = 5  // maximum score multiplier
+	ComboPulse    = 20 // how long the multiplier pulses after it rises
+)
+
+// A Combo tracks successive jumps made without resting on the ground.
+type Combo struct {
+	jumps  int        // jumps in the current combo
+	landed clock.Time // when the gopher last landed
+	Raised clock.Time // when the multiplier last rose
+}
+
+// multiplier returns the score multiplier for the combo.
+func (c *Combo) multiplier() int {
+	m := 1 + c.jumps/comboStep
+	if m > maxMultiplier {
+		m = maxMultiplier
+	}
+	return m
+}
+
+// comboJump records a jump from the ground.
+func (g *Game) comboJump() {
+	if g.LastCalc-g.Combo.landed > comboWindow {
+		g.Combo.jumps = 0
+	}
+	m := g.Combo.multiplier()
+	g.Combo.jumps++
+	if g.Combo.multiplier() > m {
+		g.Combo.Raised = g.LastCalc
+	}
+}
+
+// comboLand records the gopher landing on the ground.
+func (g *Game) comboLand() {
+	g.Combo.landed = g.LastCalc
+}
+
+// calcCombo breaks the combo if the gopher runs along the ground for too long.
+func (g *Game) calcCombo() {
+	if g.Gopher.AtRest && g.LastCalc-g.Combo.landed > comboWindow {
+		g.Combo.jumps = 0
+	}
+}
@@ -0,0 +1,40 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sim
+
+import "time"
+
+// today returns the date of the daily challenge.
+// Everyone shares the same day, whatever their time zone.
+func today() string {
+	return time.Now().UTC().Format("2006-01-02")
+}
+
+// dailySeed returns the seed of today's challenge.
+func dailySeed() int64 {
+	y, m, d := time.Now().UTC().Date()
+	return int64(y*10000 + int(m)*100 + d)
+}
+
+// SetDaily turns the daily challenge on or off.
+// In the daily challenge every player runs through the same world
+// that day, and the best score of the day is recorded separately.
+// It takes effect from the next run.
+func (g *Game) SetDaily(on bool) {
+	g.daily = on
+}
+
+// Daily reports whether the daily challenge is on.
+func (g *Game) Daily() bool {
+	return g.daily
+}
+
+// dailyBest returns the best score in today's challenge.
+func (g *Game) dailyBest() int {
+	if g.saved.DailyDate != today() {
+		return 0
+	}
+	return g.saved.DailyBest
+}
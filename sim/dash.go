@@ -0,0 +1,51 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sim
+
+const (
+	dashA        = 3   // scroll velocity multiplier while dashing
+	dashTime     = 20  // how long a dash lasts
+	dashCooldown = 300 // how long after a dash before the gopher may dash again
+)
+
+// Dash makes the gopher rush forwards for a moment,
+// during which it can't be hurt.
+func (g *Game) Dash() {
+	if !g.Choosing() && !g.Paused() && !g.takeInput(inputDash, true, 0) {
+		return
+	}
+	if g.Over() || g.Paused() || g.LastCalc < g.Gopher.dashReady {
+		return
+	}
+	g.Gopher.dashUntil = g.LastCalc + dashTime
+	g.Gopher.dashReady = g.LastCalc + dashCooldown
+}
+
+// dashing reports whether the gopher is dashing.
+func (g *Game) dashing() bool {
+	return g.LastCalc < g.Gopher.dashUntil
+}
+
+// DashCharge reports how far the dash has recharged, from 0 to 1.
+func (g *Game) DashCharge() float32 {
+	left := g.Gopher.dashReady - g.LastCalc
+	if left <= 0 {
+		return 1
+	}
+	return 1 - float32(left)/dashCooldown
+}
+
+// ScrollV returns the distance the ground scrolls this frame.
+func (g *Game) ScrollV() float32 {
+	if g.Gopher.Grab != grabNone {
+		// The gopher is stopped by a cliff.
+		return 0
+	}
+	v := g.Scroll.V + g.Gopher.drift
+	if g.dashing() {
+		return v * dashA
+	}
+	return v
+}
@@ -0,0 +1,31 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sim
+
+// When the gopher dies it is squashed against whatever it hit,
+// then bursts into feathers and tumbles off the bottom of the screen.
+const (
+	DeathSquash  = 10  // frames the gopher is held, squashed, where it died
+	SquashAmount = 0.4 // how much wider and shorter the gopher is squashed
+	deathBounce  = 1.5 // how much harder than a jump the gopher bounces off screen
+)
+
+// calcDeath plays out the death of the gopher,
+// and reports whether it is being held where it died.
+func (g *Game) calcDeath() bool {
+	if !g.Over() || g.Finished() {
+		return false
+	}
+	switch dt := g.LastCalc - g.Gopher.DeadTime; {
+	case dt < DeathSquash:
+		g.Gopher.V = 0
+		return true
+	case dt == DeathSquash && g.Gopher.V == 0:
+		// Bounce once, on the first step of the tick.
+		g.Gopher.V = g.physics.JumpV * deathBounce
+		g.emit(featherBurst, g.Scroll.X+TileWidth*GopherTile+TileWidth/2, g.Gopher.Y+TileHeight/2)
+	}
+	return false
+}
@@ -0,0 +1,114 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sim
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+)
+
+// A stage describes how hard the game is from a given distance onwards.
+type stage struct {
+	Distance         int     // score at which the stage begins
+	ScrollA          float32 // scroll acceleration, in pixels per second per second
+	GroundChangeProb int     // 1/probability of ground height change
+	GroundWobbleProb int     // 1/probability of minor ground height change
+}
+
+// A difficulty is a schedule of stages, ordered by distance.
+type difficulty []stage
+
+// The difficulty presets.
+const (
+	Easy   = "easy"
+	Normal = "normal"
+	Hard   = "hard"
+)
+
+// defaultDifficulties are used if difficulty.json can't be loaded.
+var defaultDifficulties = map[string]difficulty{
+	Easy: {
+		{Distance: 0, ScrollA: 1.8, GroundChangeProb: 8, GroundWobbleProb: 4},
+	},
+	Normal: {
+		{Distance: 0, ScrollA: 3.6, GroundChangeProb: 5, GroundWobbleProb: 3},
+	},
+	Hard: {
+		{Distance: 0, ScrollA: 7.2, GroundChangeProb: 4, GroundWobbleProb: 2},
+	},
+}
+
+// loadDifficulties reads the difficulty presets from difficulty.json, opened
+// with open, falling back to the defaults if it is missing or invalid.
+func loadDifficulties(open func(string) (io.ReadCloser, error)) map[string]difficulty {
+	if open == nil {
+		return defaultDifficulties
+	}
+	a, err := open("difficulty.json")
+	if err != nil {
+		log.Print(err)
+		return defaultDifficulties
+	}
+	defer a.Close()
+
+	var ds map[string]difficulty
+	if err := json.NewDecoder(a).Decode(&ds); err != nil {
+		log.Printf("difficulty.json: %v", err)
+		return defaultDifficulties
+	}
+	for name, d := range ds {
+		if err := d.validate(); err != nil {
+			log.Printf("difficulty.json: %s: %v", name, err)
+			return defaultDifficulties
+		}
+	}
+	for name, d := range defaultDifficulties {
+		if _, ok := ds[name]; !ok {
+			ds[name] = d
+		}
+	}
+	return ds
+}
+
+func (d difficulty) validate() error {
+	if len(d) == 0 || d[0].Distance != 0 {
+		return fmt.Errorf("first stage must begin at distance 0")
+	}
+	for i, s := range d {
+		if i > 0 && s.Distance <= d[i-1].Distance {
+			return fmt.Errorf("stage %d: distances must increase", i)
+		}
+		if s.GroundChangeProb < 1 || s.GroundWobbleProb < 1 {
+			return fmt.Errorf("stage %d: probabilities must be at least 1", i)
+		}
+	}
+	return nil
+}
+
+// at returns the stage for the given score.
+func (d difficulty) at(score int) *stage {
+	i := len(d) - 1
+	for i > 0 && d[i].Distance > score {
+		i--
+	}
+	return &d[i]
+}
+
+// SetDifficulty selects one of the difficulty presets:
+// Easy, Normal, or Hard.
+// It takes effect immediately.
+func (g *Game) SetDifficulty(name string) error {
+	d, ok := g.difficulties[name]
+	if !ok {
+		return fmt.Errorf("unknown difficulty %q", name)
+	}
+	g.difficulty = d
+	return nil
+}
+
+// difficultyNames are the difficulty presets, from easiest to hardest.
+var difficultyNames = []string{Easy, Normal, Hard}
@@ -0,0 +1,56 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sim
+
+const (
+	maxEnemies   = 3              // maximum number of enemies at once
+	enemyProb    = 30             // 1/probability of a new tile having an enemy
+	enemyStart   = TilesX * 4     // distance the gopher runs before enemies appear
+	enemySize    = TileWidth      // width and height of an enemy
+	enemyV       = -45            // horizontal velocity of enemies, relative to the ground
+	birdAmp      = TileHeight * 2 // how far birds rise and fall
+	birdPeriod   = 90             // how long it takes a bird to rise and fall
+	batSwoop     = TileWidth * 5  // how close to the gopher a bat starts to swoop
+	batSwoopRate = 3.71           // how quickly a bat closes in on the gopher as it swoops, per second
+	enemyFallV   = 120            // velocity of a knocked out enemy
+	enemyMinUp   = 3              // minimum height of an enemy above the ground, in tiles
+	enemyMaxUp   = 7              // maximum height of an enemy above the ground, in tiles
+	EnemyFlap    = 6              // how long each wing beat lasts
+	enemyCruiseY = TileHeight * 2 // how far above its cruising height a bat climbs after swooping
+)
+
+// spawnEnemy maybe places an enemy in the air above the last ground tile.
+// Birds fly in a sine wave, and bats swoop down at the gopher.
+func (g *Game) spawnEnemy() {
+	if g.Travelled < enemyStart*TileWidth || g.countEntities(EntityEnemy) >= maxEnemies || g.BossActive() || g.rng.Intn(g.escalate(enemyProb)) != 0 {
+		return
+	}
+	last := g.lastTile()
+	up := float32(enemyMinUp + g.rng.Intn(enemyMaxUp-enemyMinUp+1))
+	y := g.surfaceY(last) - up*TileHeight
+	route, tex := pathBird, TexBird
+	if g.rng.Intn(2) == 1 {
+		route, tex = pathBat, TexBat
+	}
+	x := float32(last * TileWidth)
+	g.addEntity(Entity{
+		Kind:       EntityEnemy,
+		has:        hasVelocity | hasPath | hasCollider | hasRenderable,
+		position:   position{x, y, enemySize, enemySize},
+		velocity:   velocity{vx: enemyV},
+		path:       path{route: route, baseY: y, origin: g.LastCalc},
+		collider:   collider{touches: touchHarm},
+		renderable: renderable{Tex: tex, Layer: LayerEnemies, Flap: true},
+	})
+	g.soundAt(SfxEnemy, x)
+}
+
+// knockOutEnemy knocks the enemy e out of the sky,
+// so that it falls upside down and harms nothing.
+func knockOutEnemy(e *Entity) {
+	e.has &^= hasPath | hasCollider
+	e.velocity = velocity{vy: enemyFallV}
+	e.Flap, e.Flipped = false, true
+}
@@ -0,0 +1,319 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sim
+
+import (
+	"math"
+
+	"golang.org/x/mobile/exp/sprite/clock"
+)
+
+// Everything in the world other than the gopher and the ground is an
+// entity: each is made of the components it has, and the systems below
+// move, collide, and discard all entities alike, by their components,
+// rather than each kind by its own code.
+
+// MaxEntities is the most entities there are at once.
+const MaxEntities = maxCoins + maxPickups + maxAcorns +
+	maxObstacles + maxEnemies + maxPlatforms + maxParticles
+
+const entityOffBot = TileHeight * TilesY // y-offset below which entities are off screen
+
+// Entity kinds.
+const (
+	EntityCoin     = iota // collected for coins to spend in the shop
+	EntityPickup          // collected for a power-up
+	EntityAcorn           // thrown by the gopher at things in its way
+	EntityObstacle        // in the gopher's way
+	EntityEnemy           // flying at the gopher
+	EntityPlatform        // floating above the ground, for the gopher to land on
+	EntityParticle        // a speck of dust, debris, or feather
+)
+
+// Components an entity may have, other than its position.
+const (
+	hasVelocity   = 1 << iota // it moves by itself
+	hasPath                   // it follows a path of its own
+	hasGround                 // it rests on the ground
+	hasCollider               // something happens when it touches something
+	hasLife                   // it only lasts so long
+	hasRenderable             // it is drawn
+	isBrittle                 // it breaks when it hits the ground
+)
+
+// What a collider touches.
+const (
+	touchCollect = iota // the gopher, which collects it
+	touchHarm           // the gopher, which it harms
+	touchFoes           // enemies and obstacles, which it knocks out
+)
+
+// Paths an entity may follow.
+const (
+	pathBird = iota // rise and fall in a sine wave
+	pathBat         // swoop down at the gopher
+	pathBob         // bob gently up and down
+)
+
+// Layers in which entities are drawn among the rest of the world, back to front.
+const (
+	LayerPlatforms = iota // platforms, just in front of the ground
+	LayerObstacles        // obstacles
+	LayerItems            // coins and pickups
+	LayerEnemies          // enemies, behind the gopher
+	LayerAcorns           // acorns, in front of the gopher
+	LayerParticles        // particles, in front of the rest of the world
+)
+
+// An Entity is a thing in the world other than the gopher and the ground.
+type Entity struct {
+	Kind int // what the entity is; see EntityCoin and friends
+	has  int // the components the entity has; see hasVelocity and friends
+
+	position
+	velocity
+	path
+	collider
+	life
+	renderable
+
+	P PowerUp // the power-up a pickup gives
+}
+
+// A position is where an entity is.
+type position struct {
+	X, Y float32 // position of the top-left corner, relative to the ground tiles
+	W, H float32 // size
+}
+
+// A velocity is how an entity moves by itself.
+type velocity struct {
+	vx, vy  float32 // velocity, relative to the ground
+	gravity float32 // downward acceleration
+}
+
+// A path is how an entity moves up and down by itself.
+type path struct {
+	route  int        // see pathBird and friends
+	baseY  float32    // y-offset around which the entity moves
+	phase  float32    // offset into the path's cycle, in radians
+	origin clock.Time // when the path's cycle started
+}
+
+// A collider is what an entity touches.
+type collider struct {
+	touches int  // see touchCollect and friends
+	passed  bool // has the gopher got past it?
+}
+
+// A life is how long an entity lasts.
+type life struct {
+	born clock.Time // when the entity appeared
+	span clock.Time // how long it lasts
+}
+
+// A renderable is how an entity is drawn.
+type renderable struct {
+	Tex     int  // texture
+	Layer   int  // where it is drawn; see LayerPlatforms and friends
+	Tumble  bool // flip the texture over and back as it flies
+	Flap    bool // squash the texture as it beats its wings
+	Flipped bool // draw the texture upside down
+	Hang    bool // stretch it up to hang from the top of the screen
+}
+
+func (e *Entity) box() box { return box{e.X, e.Y, e.X + e.W, e.Y + e.H} }
+
+// Drawn reports whether the entity is drawn.
+func (e *Entity) Drawn() bool {
+	return e.has&hasRenderable != 0
+}
+
+// Scale returns how big e is drawn, as a fraction of its size.
+// Entities that only last so long shrink as they expire.
+func (g *Game) Scale(e *Entity) float32 {
+	if e.has&hasLife == 0 {
+		return 1
+	}
+	return 1 - float32(g.LastCalc-e.born)/float32(e.span)
+}
+
+// addEntity adds e to the world.
+func (g *Game) addEntity(e Entity) {
+	g.Entities = append(g.Entities, e)
+}
+
+// removeEntity takes e out of the world. It is discarded by
+// sweepEntities, so that the systems can go on through the entities.
+func removeEntity(e *Entity) {
+	e.has = 0
+}
+
+// sweepEntities discards the entities that have been removed.
+func (g *Game) sweepEntities() {
+	es := g.Entities[:0]
+	for _, e := range g.Entities {
+		if e.has != 0 {
+			es = append(es, e)
+		}
+	}
+	g.Entities = es
+}
+
+// countEntities returns the number of entities of kind k.
+func (g *Game) countEntities(k int) int {
+	n := 0
+	for i := range g.Entities {
+		if e := &g.Entities[i]; e.Kind == k && e.has != 0 {
+			n++
+		}
+	}
+	return n
+}
+
+// firstEntity returns the entity of kind k added first, or nil if there is none.
+func (g *Game) firstEntity(k int) *Entity {
+	for i := range g.Entities {
+		if e := &g.Entities[i]; e.Kind == k && e.has != 0 {
+			return e
+		}
+	}
+	return nil
+}
+
+// lastEntity returns the entity of kind k added last, or nil if there is none.
+func (g *Game) lastEntity(k int) *Entity {
+	for i := len(g.Entities) - 1; i >= 0; i-- {
+		if e := &g.Entities[i]; e.Kind == k && e.has != 0 {
+			return e
+		}
+	}
+	return nil
+}
+
+// shiftEntities moves the entities along with the ground tiles
+// and discards those that have scrolled off screen.
+func (g *Game) shiftEntities() {
+	for i := range g.Entities {
+		e := &g.Entities[i]
+		e.X -= TileWidth
+		if e.X+e.W <= 0 {
+			removeEntity(e)
+		}
+	}
+	g.sweepEntities()
+}
+
+// moveEntities moves the entities that move by themselves, discarding
+// those that expire, fly off ahead of or below the screen, or break.
+func (g *Game) moveEntities() {
+	for i := range g.Entities {
+		e := &g.Entities[i]
+		if e.has&hasLife != 0 && g.LastCalc-e.born >= e.span {
+			removeEntity(e)
+			continue
+		}
+		if e.has&hasVelocity != 0 {
+			e.vy += e.gravity * g.dt()
+			e.X += e.vx * g.dt()
+			e.Y += e.vy * g.dt()
+		}
+		if e.has&hasPath != 0 {
+			g.followPath(e)
+		}
+		if e.has&hasGround != 0 {
+			e.Y = g.GroundAt(e.X+e.W/2) - e.H
+		}
+		switch {
+		case e.vx > 0 && e.X-g.Scroll.X >= g.width,
+			e.Y >= entityOffBot,
+			e.has&isBrittle != 0 && e.Y+e.H >= g.GroundAt(e.X+e.W/2):
+			removeEntity(e)
+		}
+	}
+	g.sweepEntities()
+}
+
+// followPath moves e up and down along its path.
+func (g *Game) followPath(e *Entity) {
+	cycle := func(period clock.Time) float64 {
+		return float64(e.phase) + 2*math.Pi*float64((g.LastCalc-e.origin)%period)/float64(period)
+	}
+	switch e.route {
+	case pathBird:
+		e.Y = e.baseY + birdAmp*float32(math.Sin(cycle(birdPeriod)))
+	case pathBat:
+		x0, y0, _, _ := g.GopherBounds()
+		target := e.baseY
+		if d := e.X - x0; d < batSwoop && d > 0 {
+			target = y0
+		} else if d <= 0 {
+			target = e.baseY - enemyCruiseY
+		}
+		e.Y += (target - e.Y) * g.ease(batSwoopRate)
+	case pathBob:
+		e.Y = e.baseY + platformAmp*float32(math.Sin(cycle(platformPeriod)))
+	}
+}
+
+// collideEntities checks what the entities with colliders touched as they
+// moved this step, and discards those that are used up by the touch.
+func (g *Game) collideEntities() {
+	for i := range g.Entities {
+		// Touches may add entities, moving them all, so
+		// each entity is found again after its touch.
+		if g.Entities[i].has&hasCollider != 0 && g.touch(&g.Entities[i]) {
+			removeEntity(&g.Entities[i])
+		}
+	}
+	g.sweepEntities()
+}
+
+// touch makes e do what it does to whatever it touched,
+// and reports whether e was used up by the touch.
+// Nothing may be done to e once something else has been added,
+// which moves the entities.
+func (g *Game) touch(e *Entity) bool {
+	switch e.touches {
+	case touchCollect:
+		if g.Over() || !g.gopherHits(e.box()) {
+			return false
+		}
+		switch e.Kind {
+		case EntityCoin:
+			g.Collected += g.coinValue
+			g.event(eventCoin)
+			g.soundAt(SfxCoin, e.X)
+		case EntityPickup:
+			g.applyPowerUp(e.P)
+			g.vibrate(hapticPowerUp)
+			g.sound(SfxPowerUp)
+		}
+		return true
+	case touchHarm:
+		if g.Over() || !g.gopherHits(e.box().inset(g.physics.ClimbGrace/2)) {
+			return false
+		}
+		switch e.Kind {
+		case EntityObstacle:
+			if g.Gopher.Starred {
+				g.smashObstacle()
+				return true
+			}
+			g.killGopher(causeObstacle)
+		case EntityEnemy:
+			if g.Invulnerable() || g.dashing() || g.Gopher.Starred {
+				// Gopher knocks the enemy out.
+				knockOutEnemy(e)
+				return false
+			}
+			g.killGopher(causeEnemy)
+		}
+		return false
+	case touchFoes:
+		return g.knockOut(e)
+	}
+	return false
+}
@@ -0,0 +1,19 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sim
+
+// When the gopher dies the world stops for a moment
+// and the screen flashes white, then red.
+const (
+	hitStop    = 6   // frames the world stops for when the gopher dies
+	FlashTime  = 18  // frames the screen flashes for
+	FlashWhite = 4   // frames of the flash that are white
+	FlashAlpha = 0.6 // opacity of the flash at its brightest
+)
+
+// hitStopped reports whether the world is stopped by the gopher's death.
+func (g *Game) hitStopped() bool {
+	return g.Over() && !g.Finished() && g.LastCalc-g.Gopher.DeadTime < hitStop
+}
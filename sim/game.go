@@ -0,0 +1,902 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package sim simulates the gopher's runs: the ground, the gopher running
+// over it, and everything in its way. It draws nothing, so that runs may be
+// played headless, by tests, bots, and servers checking scores.
+package sim
+
+import (
+	"io"
+	"math/rand"
+	"time"
+
+	"golang.org/x/mobile/exp/sprite/clock"
+)
+
+const (
+	TileWidth, TileHeight = 16, 16 // width and height of each tile
+	TilesX, TilesY        = 16, 16 // number of horizontal tiles
+
+	GopherTile = 1 // which tile the gopher is standing on (0-indexed)
+
+	// Velocities are in pixels per second, and times are in ticks
+	// of the clock; see ClockRate. The gopher's physics are in physics.json.
+	initScrollV = 60 // initial scroll velocity
+
+	initMaxFlaps = 1 // number of flaps allowed in mid-air, by default
+
+	slideTime = 40 // how long the gopher may slide for
+
+	glideV = 30 // maximum falling velocity while gliding
+
+	resultsDelay = 240 // how long the run is over before its results are shown
+
+	groundMin   = TileHeight * (TilesY - 2*TilesY/5)
+	groundMax   = TileHeight * TilesY
+	InitGroundY = TileHeight * (TilesY - 1)
+
+	InitLives        = 3   // number of crashes the gopher survives, plus one
+	invulnerableTime = 120 // how long the gopher is invulnerable after a crash
+
+	ScoreDigits = 6 // maximum number of score digits shown
+)
+
+type Game struct {
+	Gopher struct {
+		Y         float32    // y-offset
+		V         float32    // velocity
+		AtRest    bool       // is the gopher on the ground?
+		flaps     int        // how many times the gopher has flapped since it became airborne
+		DeadTime  clock.Time // when the gopher died
+		cause     deathCause // what killed the gopher
+		held      bool       // is the button held down?
+		Gliding   bool       // is the gopher gliding?
+		Sliding   bool       // is the gopher sliding along the ground?
+		Grab      int        // wall grab state; see grabNone and friends
+		grabTime  clock.Time // when the wall grab state last changed
+		grabY     float32    // y-offset when the gopher started climbing
+		slidTime  clock.Time // when the gopher started sliding
+		dashUntil clock.Time // when the current dash ends
+		dashReady clock.Time // when the gopher may dash again
+		Lives     int        // remaining lives, including this one
+		safeTime  clock.Time // when the gopher stops being invulnerable
+		drift     float32    // horizontal push of the wind
+		Shielded  bool       // will the gopher survive its next crash?
+		Shattered clock.Time // when the gopher's shield last broke
+		Starred   bool       // is the gopher invincible?
+		jumped    clock.Time // when the gopher last jumped from the ground
+		boosting  bool       // is the jump still rising harder while the button is held?
+		grounded  clock.Time // when the gopher was last on the ground
+		buffered  bool       // was a jump pressed in mid-air, to be made on landing?
+		pressed   clock.Time // when the buffered jump was pressed
+		dx, dy    float32    // how far the gopher moved this step, relative to the ground
+		breath    float32    // how many seconds the gopher has been under water
+		Slip      float32    // how far the gopher has skidded ahead of its column
+		slipV     float32    // how fast the gopher is skidding
+	}
+	Scroll struct {
+		X float32 // x-offset
+		V float32 // velocity
+	}
+	GroundY     [WorldTiles]float32 // ground y-offsets
+	GroundTex   [WorldTiles]int     // ground texture
+	GroundType  [WorldTiles]int     // ground tile type; see tileNormal and friends
+	groundSlope [WorldTiles]bool    // whether each ground tile ramps from the height of the one before
+	GroundBiome [WorldTiles]int     // biome of each ground tile; see biomeMeadow and friends
+	Scenery     [WorldTiles]int     // scenery on each ground tile; see SceneryNone and friends
+	Tiles       int                 // number of ground tiles in use
+	pitLeft     int                 // number of tiles of the current pit still to come
+	pitEdge     float32             // ground y-offset beside the current pit
+	poolLeft    int                 // number of tiles of the current pool still to come
+	poolEdge    float32             // ground y-offset beside the current pool
+	Entities    []Entity            // everything else in the world, in the order it appeared
+	Collected   int                 // coins collected this run
+	coinValue   int                 // coins awarded for each coin collected
+	Magnetised  bool                // are coins drawn towards the gopher?
+	jumpV       float32             // jump velocity
+	Zones       []Zone              // low gravity zones
+	boss        boss                // the boss encounter
+	thrown      clock.Time          // when the gopher last threw an acorn
+	gust        gust                // the next or current gust of wind
+	Leaves      []Leaf              // leaves blowing in the wind
+	Fade        Transition          // the fade between scenes
+	state       State               // what the game is doing
+	summaryItem int                 // the summary option shown; see summaryRetry and friends
+	pauseItem   int                 // the pause menu option shown; see pauseResume and friends
+	shake       shake               // the camera shake
+	Stretch     Stretch             // the gopher's squash and stretch
+	active      []ActivePowerUp     // power-ups applied to the gopher
+	Travelled   float32             // how far the gopher has run
+	points      float32             // distance run, weighted by the score multiplier
+	Combo       Combo               // successive jumps, for the score multiplier
+	checkpoint  checkpoint          // the last checkpoint passed
+	saved       progress            // progress saved across launches
+	savedJSON   []byte              // saved as last loaded or written, in JSON; see writeProgress
+	runTime     clock.Time          // how long the gopher has been running this run
+	splits      []clock.Time        // time attack split times this run
+	nearMisses  int                 // obstacles the gopher only just got past this run
+	xpPaid      int                 // XP already paid for this run
+	levelUp     clock.Time          // when the player last levelled up
+	Toast       string              // name of the achievement last unlocked
+	ToastTime   clock.Time          // when it was unlocked
+
+	clips Clips                 // records the runs to share
+	buzz  func(d time.Duration) // buzzes the device, or nil if it can't
+
+	restyler func() // paints the textures again in a new style
+
+	open func(name string) (io.ReadCloser, error) // opens the data files, or nil if there are none
+
+	difficulties map[string]difficulty // the difficulty presets
+	physics      physics               // how the gopher moves
+	difficulty   difficulty            // the current difficulty
+	settings     Settings              // the player's options
+
+	Day         float32    // time of day, from 0 to 1
+	NightGround bool       // use darker ground textures at night
+	dark        float32    // how dark it is around the gopher, from 0 to 1
+	maxFlaps    int        // number of flaps allowed in mid-air
+	Character   int        // index of the character being played or shown
+	Mode        Mode       // the rules of the game
+	daily       bool       // is this the daily challenge?
+	rng         *rand.Rand // source of randomness for the world
+	src         rngSource  // state of rng
+	seed        int64      // seed of every run's world, if seeded
+	seeded      bool       // does every run go through the same world?
+	started     clock.Time // when this run started
+	recording   replay     // this run, so far, to be watched again once it ends
+	replaying   bool       // is a replay of the last run being shown?
+	played      int        // inputs of the replay already made
+	feeding     bool       // are the replay's inputs being made?
+	ShopItem    int        // index of the item shown in the shop
+	shopMsg     string     // why the last purchase or refund failed
+	timeScale   float32    // ticks simulated per tick of the clock
+	steps       float32    // ticks owed to the simulation
+	LastCalc    clock.Time // when we last calculated a frame
+	Prev        Snapshot   // where things were before the last frame, for drawing
+	Frac        float32    // how far the time drawn is past LastCalc, in frames
+	tilt        float32    // how far the device is tilted, with the tilt controls
+	sounds      []Sound    // sound effects waiting to be played
+	stingUntil  clock.Time // when another stinger may play
+	beatBest    bool       // has this run beaten the best score yet?
+
+	width float32 // width of the world shown
+}
+
+// An Option configures a new game.
+type Option func(*Game)
+
+// WithSeed makes every run of the game go through the world grown
+// from seed, rather than a new one each time.
+func WithSeed(seed int64) Option {
+	return func(g *Game) {
+		g.seed = seed
+		g.seeded = true
+		g.rng.Seed(seed)
+	}
+}
+
+// WithAssets reads the data files, physics.json and difficulty.json,
+// with open. Games without them play by the built-in defaults.
+func WithAssets(open func(name string) (io.ReadCloser, error)) Option {
+	return func(g *Game) {
+		g.open = open
+	}
+}
+
+// WithClips records the end of each run with c, for the player to share.
+func WithClips(c Clips) Option {
+	return func(g *Game) {
+		g.clips = c
+	}
+}
+
+// WithVibrator buzzes the device with vibrate, for as long as it is given,
+// on crashes, big landings, and power-ups.
+func WithVibrator(vibrate func(d time.Duration)) Option {
+	return func(g *Game) {
+		g.buzz = vibrate
+	}
+}
+
+// NewGame returns a game played by the rules of the given mode.
+func NewGame(mode Mode, opts ...Option) *Game {
+	var g Game
+	g.Mode = mode
+	g.clips = noClips{}
+	g.src.Seed(time.Now().UnixNano())
+	g.rng = rand.New(&g.src)
+	for _, opt := range opts {
+		opt(&g)
+	}
+	g.difficulties = loadDifficulties(g.open)
+	g.difficulty = g.difficulties[Normal]
+	g.physics = loadPhysics(g.open)
+	g.settings = loadSettings()
+	g.applySettings()
+	g.NightGround = true
+	g.maxFlaps = initMaxFlaps
+	g.levelUp = -1
+	g.width = WorldW
+	g.state = StatePlaying
+	g.reset()
+	if !g.resumeRun() {
+		g.setState(StateMenu)
+	}
+	return &g
+}
+
+func (g *Game) reset() {
+	g.started = g.LastCalc
+	if g.replaying {
+		g.played = 0
+	} else {
+		g.recording = replay{seed: g.runSeed(), ok: true}
+	}
+	g.rng.Seed(g.recording.seed)
+	g.Gopher.Y = 0
+	g.Gopher.V = 0
+	g.Scroll.X = 0
+	g.Scroll.V = initScrollV
+	g.Tiles = viewTiles(g.width)
+	for i := range g.GroundY {
+		g.GroundY[i] = InitGroundY
+		g.GroundTex[i] = g.randomGroundTexture()
+		g.GroundType[i] = tileNormal
+		g.groundSlope[i] = false
+		g.GroundBiome[i] = biomeMeadow
+		g.Scenery[i] = SceneryNone
+	}
+	g.pitLeft = 0
+	g.pitEdge = InitGroundY
+	g.poolLeft = 0
+	g.poolEdge = InitGroundY
+	g.Gopher.AtRest = false
+	g.Gopher.flaps = 0
+	g.Gopher.DeadTime = 0
+	g.Gopher.cause = causeCliff
+	g.Gopher.held = false
+	g.Gopher.Gliding = false
+	g.Gopher.Sliding = false
+	g.Gopher.Grab = grabNone
+	g.Gopher.grabTime = 0
+	g.Gopher.grabY = 0
+	g.Gopher.slidTime = 0
+	g.Gopher.dashUntil = 0
+	g.Gopher.dashReady = 0
+	g.Gopher.Lives = InitLives
+	g.Gopher.safeTime = 0
+	g.Gopher.drift = 0
+	g.Gopher.Shielded = false
+	g.Gopher.Starred = false
+	g.Gopher.Shattered = -ShatterTime
+	g.Gopher.jumped = 0
+	g.Gopher.boosting = false
+	g.Gopher.grounded = 0
+	g.Gopher.buffered = false
+	g.Gopher.pressed = 0
+	g.Gopher.breath = 0
+	g.Gopher.Slip = 0
+	g.Gopher.slipV = 0
+	g.stingUntil = 0
+	g.beatBest = false
+	g.Entities = g.Entities[:0]
+	g.Collected = 0
+	g.coinValue = 1
+	g.Magnetised = false
+	g.jumpV = g.physics.JumpV * Characters[g.Character].jump
+	g.Zones = g.Zones[:0]
+	g.boss = boss{next: bossEvery}
+	g.thrown = -acornCooldown
+	g.timeScale = 1
+	g.steps = 0
+	g.Prev = g.snapshot()
+	g.gust = g.nextGust(g.LastCalc)
+	g.Leaves = g.Leaves[:0]
+	g.shake = shake{}
+	g.Stretch = Stretch{}
+	g.clips.Reset()
+	g.active = g.active[:0]
+	g.Travelled = 0
+	g.points = 0
+	g.Combo = Combo{}
+	g.checkpoint = checkpoint{}
+	g.runTime = 0
+	g.splits = nil
+	g.nearMisses = 0
+	g.xpPaid = 0
+	g.saved = loadProgress()
+	g.savedJSON = progressJSON(g.saved)
+	if !g.Choosing() {
+		// A new run starts straight away,
+		// unless the player is still choosing a character.
+		g.setState(StatePlaying)
+		g.Character = g.chosenCharacter()
+		g.maxFlaps = Characters[g.Character].flaps
+	}
+	g.dealMissions()
+}
+
+// ReloadData loads the difficulty presets and physics again.
+func (g *Game) ReloadData() {
+	g.difficulties = loadDifficulties(g.open)
+	g.physics = loadPhysics(g.open)
+	g.applySettings()
+}
+
+// Frame returns the frame for the given time t
+// when each frame is displayed for duration d.
+func Frame(t, d clock.Time, frames ...int) int {
+	total := int(d) * len(frames)
+	return frames[(int(t)%total)/int(d)]
+}
+
+const (
+	TexGopherRun1 = iota
+	TexGopherRun2
+	TexGopherFlap1
+	TexGopherFlap2
+	TexGopherDead1
+	TexGopherDead2
+	TexGopherSlide
+	TexGopherSwim
+	TexGopherGlide
+	TexGround1
+	TexGround2
+	TexGround3
+	TexGround4
+	TexEarth
+	TexRock
+	TexLog
+	TexPipe
+	TexCoin
+	TexSuperJump
+	TexDoubleCoins
+	TexSky
+	TexDash
+	TexShade
+	TexPlatform
+	TexSpring
+	TexBird
+	TexBat
+	TexSpikes
+	TexLava
+	TexIce
+	TexMole
+	TexLeaf
+	TexMagnet
+	TexAura
+	TexShield
+	TexRing
+	TexShard
+	TexSlowMo
+	TexStar
+	TexAcorn
+	TexCap
+	TexTopHat
+	TexCrown
+	TexFlag
+	TexCloud
+	TexHills
+	TexGrass
+	TexDust
+	TexDebris
+	TexFeather
+	TexFade
+	TexLight1
+	TexLight2
+	TexLight3
+	TexLight4
+	TexTree
+	TexPebbles
+	TexFlowers
+	TexFence
+	TexBalloon
+	TexWater
+	TexFlashWhite
+	TexFlashRed
+	TexCount
+)
+
+func (g *Game) randomGroundTexture() int {
+	return TexGround1 + g.rng.Intn(4)
+}
+
+func (g *Game) Press(down bool) {
+	switch g.state {
+	case StatePaused:
+		if down {
+			g.choosePauseOption()
+		}
+		return
+	case StateShop:
+		if down {
+			g.buySelected()
+		}
+		return
+	case StateMenu:
+		if down {
+			g.chooseCharacter()
+		}
+		return
+	case StateResults:
+		if down {
+			g.chooseSummaryOption()
+		}
+		return
+	case StateDead:
+		if down && g.CanContinue() {
+			g.continueRun()
+		}
+		// Player can't control a dead gopher.
+		return
+	}
+	if !g.takeInput(inputPress, down, 0) {
+		return
+	}
+
+	g.Gopher.held = down
+	if down && g.Gopher.Grab == GrabHanging {
+		// Gopher may scramble up a cliff it is hanging on to.
+		g.climbWall()
+		return
+	}
+	if down {
+		switch {
+		case g.submerged():
+			// Gopher strokes up through water.
+			g.stroke()
+		case g.InWater():
+			// Gopher may leap out of water with its head above it.
+			g.jump()
+		case g.Gopher.AtRest || g.coyote():
+			// Gopher may jump from the ground,
+			// or just after running off its edge.
+			g.jump()
+		case g.Gopher.flaps < g.maxFlaps:
+			// Gopher may flap a few times in mid-air.
+			g.Gopher.flaps++
+			g.Gopher.V = g.physics.FlapV
+			g.stretchGopher(flapStretch)
+			g.event(eventFlap)
+			g.sound(SfxFlap)
+		default:
+			// Gopher jumps if it lands soon after.
+			g.bufferJump()
+		}
+	} else {
+		// Stop gopher rising on button release,
+		// unless it is swimming.
+		if g.Gopher.V < 0 && !g.InWater() {
+			g.Gopher.V = 0
+		}
+	}
+}
+
+// Slide starts or stops the gopher sliding along the ground,
+// under obstacles that it would otherwise run into.
+func (g *Game) Slide(down bool) {
+	if !g.Choosing() && !g.Paused() && !g.takeInput(inputSlide, down, 0) {
+		return
+	}
+	if !down {
+		g.Gopher.Sliding = false
+		return
+	}
+	if g.Over() || g.Paused() || !g.Gopher.AtRest {
+		// Gopher may only slide along the ground.
+		return
+	}
+	g.Gopher.Sliding = true
+	g.Gopher.slidTime = g.LastCalc
+}
+
+// SetMaxFlaps sets the number of times the gopher may flap in mid-air.
+func (g *Game) SetMaxFlaps(n int) {
+	if n < 0 {
+		n = 0
+	}
+	g.maxFlaps = n
+}
+
+func (g *Game) Update(now clock.Time) {
+	g.calcSky(now)
+	g.calcTransition(now)
+	switch g.state {
+	case StatePaused:
+		return
+	case StateDead:
+		if now-g.Gopher.DeadTime > resultsDelay {
+			// Sum up the run once it has been over for a while,
+			// and let the player choose whether to go again.
+			g.setState(StateResults)
+		}
+	case StateMenu, StateShop:
+		// Nothing moves while the player chooses,
+		// and the gopher stands ready to be shown off.
+		g.Gopher.Y = g.GroundY[GopherTile] - TileHeight
+		g.Gopher.AtRest = true
+		g.LastCalc = now
+		g.started = now
+		g.Prev = g.snapshot()
+		return
+	}
+
+	// Compute game states up to now,
+	// skipping frames while time is slowed.
+	for ; g.LastCalc < now; g.LastCalc++ {
+		g.Prev = g.snapshot()
+		g.feedInputs()
+		if g.hitStopped() {
+			continue
+		}
+		for g.steps += g.timeScale; g.steps >= 1; g.steps-- {
+			for i := g.substeps(); i > 0; i-- {
+				g.calcFrame()
+			}
+			g.calcTick()
+		}
+	}
+}
+
+func (g *Game) calcFrame() {
+	g.calcScroll()
+	g.moveEntities()
+	g.calcGopher()
+	g.calcCoins()
+	g.calcObstacles()
+	g.calcBoss()
+	g.calcWind()
+	g.calcLighting()
+	g.calcPowerUps()
+	g.calcCombo()
+	g.calcScore()
+	g.calcCheckpoint()
+}
+
+func (g *Game) calcScroll() {
+	// Compute velocity.
+	if g.Over() {
+		// Decrease scroll speed when the gopher dies.
+		g.Scroll.V += g.physics.DeadScrollA * g.dt()
+		if g.Scroll.V < 0 {
+			g.Scroll.V = 0
+		}
+	} else {
+		// Increase scroll speed.
+		g.Scroll.V += g.difficulty.at(g.Distance()).ScrollA * g.dt()
+	}
+
+	// Compute offset.
+	g.Gopher.dx = g.ScrollV() * g.dt()
+	g.Scroll.X += g.Gopher.dx
+
+	// Create new ground tiles if we need to.
+	for g.Scroll.X > TileWidth {
+		g.newGroundTile()
+
+		// Check whether the gopher has crashed.
+		// Do this for each new ground tile so that when the scroll
+		// velocity is >TileWidth a step it can't pass through the ground.
+		if !g.Over() && g.gopherCrashed() {
+			g.emit(debrisBurst, g.Scroll.X+TileWidth*(GopherTile+1), g.Gopher.Y+TileHeight)
+			if g.canGrab() {
+				g.grabWall()
+			} else {
+				g.killGopher(causeCliff)
+			}
+		}
+	}
+}
+
+func (g *Game) calcGopher() {
+	y := g.Gopher.Y
+	defer func() { g.Gopher.dy = g.Gopher.Y - y }()
+	if g.calcDeath() {
+		return
+	}
+	if g.Gopher.Grab != grabNone {
+		// Gopher is on a cliff face.
+		g.calcGrab()
+		return
+	}
+
+	// Compute velocity.
+	windX, windY := g.windForce()
+	g.Gopher.drift = windX
+	if g.dashing() {
+		// Gopher dashes straight ahead.
+		g.Gopher.V = 0
+	} else {
+		g.Gopher.V += (g.gopherGravity() + windY + g.tiltV()) * g.dt()
+		g.boostJump()
+	}
+
+	// Hold the button while falling to glide.
+	g.Gopher.Gliding = g.Gopher.held && g.Gopher.V > glideV && !g.InWater()
+	if g.Gopher.Gliding {
+		g.Gopher.V = glideV
+	}
+	g.calcSwim()
+	g.calcSlip()
+
+	// Compute offset.
+	g.Gopher.Y += g.Gopher.V * g.dt()
+
+	g.clampToGround()
+	if !g.Over() && g.gopherFell() {
+		g.killGopher(causePit)
+	}
+	if g.Gopher.Sliding && (!g.Gopher.AtRest || g.LastCalc-g.Gopher.slidTime > slideTime) {
+		// Stand up after a while, or when leaving the ground.
+		g.Gopher.Sliding = false
+	}
+	g.collideEntities()
+}
+
+func (g *Game) calcScore() {
+	if g.Over() {
+		// Dead gophers don't score.
+		return
+	}
+	metres := g.Distance()
+	g.Travelled += g.ScrollV() * g.dt()
+	milestone := g.Distance()/stingerEvery > metres/stingerEvery
+	for ; metres < g.Distance(); metres++ {
+		g.event(eventMetre)
+	}
+	g.points += g.ScrollV() * g.dt() * float32(g.Multiplier())
+	g.calcStingers(milestone)
+}
+
+// Distance returns the distance the gopher has run, in tiles.
+func (g *Game) Distance() int {
+	return int(g.Travelled / TileWidth)
+}
+
+// Score returns the distance the gopher has run, in tiles,
+// weighted by the score multiplier at each point of the run.
+func (g *Game) Score() int {
+	return int(g.points / TileWidth)
+}
+
+func (g *Game) newGroundTile() {
+	// Compute next ground y-offset.
+	next := g.nextGroundY()
+	nextTex := g.randomGroundTexture()
+	nextType := g.nextGroundType(next)
+	nextSlope := g.nextSlope(next, nextType)
+	nextBiome := biomeAt(g.Distance())
+
+	// Shift ground tiles to the left.
+	g.Scroll.X -= TileWidth
+	g.Prev.ScrollX -= TileWidth
+	copy(g.GroundY[:], g.GroundY[1:])
+	copy(g.GroundTex[:], g.GroundTex[1:])
+	copy(g.GroundType[:], g.GroundType[1:])
+	copy(g.groundSlope[:], g.groundSlope[1:])
+	copy(g.GroundBiome[:], g.GroundBiome[1:])
+	copy(g.Scenery[:], g.Scenery[1:])
+	last := g.lastTile()
+	g.GroundY[last] = next
+	g.GroundTex[last] = nextTex
+	g.GroundType[last] = nextType
+	g.groundSlope[last] = nextSlope
+	g.GroundBiome[last] = nextBiome
+
+	g.shiftEntities()
+	g.spawnObstacle()
+	g.Scenery[last] = g.nextScenery(last)
+	g.spawnCoin()
+	g.spawnPickup()
+	g.spawnPlatform()
+	g.shiftZones()
+	g.spawnZone()
+	g.spawnEnemy()
+}
+
+func (g *Game) nextGroundY() float32 {
+	if g.BossActive() {
+		// The ground is flat while the boss gives chase.
+		g.pitLeft = 0
+		g.poolLeft = 0
+		return g.surfaceY(g.lastTile())
+	}
+	if y, ok := g.nextPitY(); ok {
+		return y
+	}
+	if y, ok := g.nextPoolY(); ok {
+		return y
+	}
+	prev := g.GroundY[g.lastTile()]
+	st := g.difficulty.at(g.Distance())
+	b := &Biomes[biomeAt(g.Distance())]
+	changeProb, wobbleProb := st.GroundChangeProb, st.GroundWobbleProb
+	if b.groundChangeProb != 0 {
+		changeProb = b.groundChangeProb
+	}
+	if b.groundWobbleProb != 0 {
+		wobbleProb = b.groundWobbleProb
+	}
+	if change := g.rng.Intn(changeProb) == 0; change {
+		return (b.groundMax-b.groundMin)*g.rng.Float32() + b.groundMin
+	}
+	if wobble := g.rng.Intn(wobbleProb) == 0; wobble {
+		return prev + (g.rng.Float32()-0.5)*g.physics.ClimbGrace
+	}
+	return prev
+}
+
+func (g *Game) gopherCrashed() bool {
+	if g.groundSlope[GopherTile+1] {
+		// Gopher runs up slopes rather than into them.
+		return false
+	}
+	b := g.gopherBox()
+	b.y1 -= g.physics.ClimbGrace
+	return b.overlaps(g.groundBox(GopherTile + 1))
+}
+
+// GopherBounds returns the gopher's bounding box,
+// relative to the ground tiles.
+func (g *Game) GopherBounds() (x0, y0, x1, y1 float32) {
+	x0 = GopherTile*TileWidth + g.Scroll.X + g.Gopher.Slip
+	y0 = g.Gopher.Y
+	y1 = y0 + TileHeight
+	if g.Gopher.Sliding {
+		y0 += TileHeight / 2
+	}
+	return x0, y0, x0 + TileWidth, y1
+}
+
+// Invulnerable reports whether the gopher recently lost a life
+// and so can't be hurt.
+func (g *Game) Invulnerable() bool {
+	return g.LastCalc < g.Gopher.safeTime
+}
+
+// A deathCause is what killed the gopher.
+type deathCause int
+
+const (
+	causeCliff    deathCause = iota // crashed into a cliff
+	causeObstacle                   // ran into an obstacle
+	causePit                        // fell into a pit
+	causeEnemy                      // flew into an enemy
+	causeHazard                     // touched hazardous ground
+	causeBoss                       // caught by the boss
+	causeDrown                      // stayed under water too long
+	causeFinish                     // crossed the sprint's finish line
+)
+
+func (g *Game) killGopher(cause deathCause) {
+	g.shakeCamera(crashShake)
+	g.sound(SfxCrash)
+	if g.Mode == Zen {
+		// Bounce back and carry on.
+		g.recoverGopher(cause)
+		if g.Gopher.V >= 0 {
+			g.Gopher.V = g.physics.FlapV
+		}
+		g.Gopher.safeTime = g.LastCalc + invulnerableTime
+		return
+	}
+	if g.Invulnerable() || g.dashing() || g.Gopher.Starred {
+		g.recoverGopher(cause)
+		return
+	}
+	if g.Gopher.Shielded {
+		g.breakShield()
+		g.recoverGopher(cause)
+		return
+	}
+	g.Combo.jumps = 0
+	if g.Gopher.Lives--; g.Gopher.Lives > 0 {
+		// Lose a life, but keep running.
+		g.Gopher.safeTime = g.LastCalc + invulnerableTime
+		g.recoverGopher(cause)
+		return
+	}
+
+	g.setState(StateDead)
+	g.Gopher.DeadTime = g.LastCalc
+	g.Gopher.cause = cause
+	g.Gopher.V = 0 // Held still, then bounced off screen by calcDeath.
+	g.event(eventDeath)
+	g.vibrate(hapticDeath)
+	g.endRun()
+}
+
+// endRun banks the coins, XP, and score of the run just ended.
+func (g *Game) endRun() {
+	if g.replaying {
+		// The run was counted when it was first played.
+		return
+	}
+	g.saved.Coins += g.Collected
+	g.endRunMissions()
+	g.awardXP()
+	g.recordBest()
+	g.saveProgress()
+}
+
+// recoverGopher puts the gopher back on its feet after it survives a crash.
+func (g *Game) recoverGopher(cause deathCause) {
+	switch cause {
+	case causePit:
+		g.fillPits()
+		g.Gopher.Y = g.GroundY[GopherTile] - TileHeight
+		g.Gopher.V = 0
+		return
+	case causeHazard:
+		// Hop off the hazard.
+		g.Gopher.V = g.physics.JumpV
+		return
+	case causeDrown:
+		// Catch a breath and swim for the surface.
+		g.Gopher.breath = 0
+		g.stroke()
+		return
+	}
+	g.liftGopher()
+}
+
+// liftGopher moves the gopher onto the ground ahead
+// if it has crashed into a cliff.
+func (g *Game) liftGopher() {
+	if y := g.GroundY[GopherTile+1] - TileHeight; g.Gopher.Y > y {
+		g.Gopher.Y = y
+		g.Gopher.V = 0
+	}
+}
+
+func (g *Game) clampToGround() {
+	if g.Over() && !g.Finished() {
+		// Allow the gopher to fall through ground when dead.
+		return
+	}
+
+	// Compute the minimum offset of the ground beneath the gopher,
+	// and which ground tile it is (or -1 for a platform).
+	// The gopher stands on the tile it is on, and on the next one
+	// as soon as it starts running on to it.
+	x0, _, x1, _ := g.GopherBounds()
+	minY, under := g.groundUnder(x0, x1)
+	if y, ok := g.platformBelow(); ok && y < minY {
+		minY, under = y, -1
+	}
+
+	// Prevent the gopher from falling through the ground.
+	maxGopherY := minY - TileHeight
+	wasAtRest := g.Gopher.AtRest
+	g.Gopher.AtRest = false
+	if wasAtRest && g.Gopher.V >= 0 && under >= 0 && g.groundSlope[under] && g.Gopher.Y >= maxGopherY-slopeSnap {
+		// Gopher runs down slopes rather than off them.
+		g.Gopher.Y = maxGopherY
+	}
+	if g.Gopher.Y >= maxGopherY {
+		landV := g.Gopher.V
+		g.Gopher.V = 0
+		g.Gopher.Y = maxGopherY
+		g.Gopher.flaps = 0
+		if under >= 0 && g.landOn(under) {
+			return
+		}
+		g.Gopher.AtRest = true
+		g.Gopher.grounded = g.LastCalc
+		if !wasAtRest {
+			g.emit(dustBurst, g.Scroll.X+TileWidth*GopherTile+TileWidth/2, g.Gopher.Y+TileHeight)
+			g.stretchGopher(landingSquash(landV / g.hardLandingV()))
+			g.landOnIce(landV)
+			g.sound(SfxLand)
+			if landV > g.hardLandingV() {
+				g.shakeCamera(landingShake)
+				g.vibrate(hapticLanding)
+			}
+			g.comboLand()
+			g.jumpBuffered()
+		}
+	}
+}
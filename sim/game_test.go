@@ -0,0 +1,76 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sim_test
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/adg/game/sim"
+	"golang.org/x/mobile/exp/sprite/clock"
+)
+
+func TestMain(m *testing.M) {
+	// Keep the tests' settings, progress and runs out of the user's own.
+	dir, err := ioutil.TempDir("", "sim")
+	if err != nil {
+		panic(err)
+	}
+	os.Setenv("HOME", dir)
+	os.Setenv("XDG_CONFIG_HOME", dir)
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+// maxTicks bounds how long a test waits on the game.
+const maxTicks = sim.ClockRate * 60 * 10
+
+// start leaves the menu and waits for the run to start,
+// stepping g from time now, and returns the time it started.
+func start(t *testing.T, g *sim.Game, now clock.Time) clock.Time {
+	g.Press(true)
+	g.Press(false)
+	return step(t, g, now, func(clock.Time) bool { return g.State() == sim.StatePlaying })
+}
+
+// step updates g from time now until done reports true at a tick,
+// and returns the time it stopped.
+func step(t *testing.T, g *sim.Game, now clock.Time, done func(now clock.Time) bool) clock.Time {
+	for end := now + maxTicks; !done(now); now++ {
+		if now == end {
+			t.Fatalf("game stuck in state %v", g.State())
+		}
+		g.Update(now)
+	}
+	return now
+}
+
+// play presses the button in a steady rhythm until the run is over,
+// and returns the time it ended.
+func play(t *testing.T, g *sim.Game, now clock.Time) clock.Time {
+	return step(t, g, now, func(now clock.Time) bool {
+		switch now % 45 {
+		case 0:
+			g.Press(true)
+		case 12:
+			g.Press(false)
+		}
+		return g.Over()
+	})
+}
+
+func TestHeadlessRun(t *testing.T) {
+	g := sim.NewGame(sim.Endless, sim.WithSeed(1))
+	g.SetWidth(sim.WorldW)
+	play(t, g, start(t, g, 0))
+	if g.Travelled == 0 {
+		t.Error("gopher didn't run")
+	}
+	if n := len(g.Entities); n > sim.MaxEntities {
+		t.Errorf("%d entities, want at most %d", n, sim.MaxEntities)
+	}
+}
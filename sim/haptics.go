@@ -0,0 +1,26 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sim
+
+import "time"
+
+const maxVibration = 3 // strongest vibration level
+
+// How long the device vibrates for each event, at the strongest level.
+const (
+	hapticDeath   = 150 * time.Millisecond
+	hapticLanding = 40 * time.Millisecond
+	hapticPowerUp = 20 * time.Millisecond
+)
+
+// vibrate buzzes the device for up to d, weakened by the player's
+// vibration level, unless they have switched vibration off.
+// The device buzzes in the background, so as not to hold up the game.
+func (g *Game) vibrate(d time.Duration) {
+	if g.buzz == nil || !g.settings.Vibration || g.settings.VibrationLevel <= 0 {
+		return
+	}
+	go g.buzz(d * time.Duration(g.settings.VibrationLevel) / maxVibration)
+}
@@ -0,0 +1,56 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sim
+
+const (
+	iceProb     = 20            // 1/probability of a new tile starting a stretch of ice
+	iceStart    = TilesX * 3    // distance the gopher runs before ice appears
+	iceRun      = 5             // 1/probability of a stretch of ice ending at each tile
+	iceSlip     = 0.3           // how much of its landing speed the gopher skids forward on ice
+	iceSpring   = 20            // how hard the gopher is pulled back into its column on ice
+	iceFriction = 1.5           // how quickly a skid slows on ice
+	grip        = 6             // how quickly the gopher finds its feet on ordinary ground
+	maxSlip     = TileWidth / 4 // furthest the gopher may skid from its column
+)
+
+// nextIce reports whether the next ground tile is icy.
+func (g *Game) nextIce() bool {
+	if g.GroundType[g.lastTile()] == TileIce {
+		return g.rng.Intn(iceRun) != 0
+	}
+	return g.Travelled >= iceStart*TileWidth && g.rng.Intn(iceProb) == 0
+}
+
+// onIce reports whether the gopher is standing on ice.
+func (g *Game) onIce() bool {
+	return g.Gopher.AtRest && g.GroundType[g.footTile()] == TileIce
+}
+
+// landOnIce sets the gopher skidding if it landed on ice with speed v.
+func (g *Game) landOnIce(v float32) {
+	if g.onIce() {
+		g.Gopher.slipV += v * iceSlip
+	}
+}
+
+// calcSlip moves the gopher within its column as it skids on ice,
+// shifting when it reaches what lies ahead.
+func (g *Game) calcSlip() {
+	switch {
+	case g.onIce():
+		g.Gopher.slipV -= g.Gopher.Slip * iceSpring * g.dt()
+		g.Gopher.slipV -= g.Gopher.slipV * g.ease(iceFriction)
+	case g.Gopher.AtRest:
+		g.Gopher.slipV = 0
+		g.Gopher.Slip -= g.Gopher.Slip * g.ease(grip)
+	}
+	g.Gopher.Slip += g.Gopher.slipV * g.dt()
+	if g.Gopher.Slip > maxSlip {
+		g.Gopher.Slip, g.Gopher.slipV = maxSlip, 0
+	}
+	if g.Gopher.Slip < -maxSlip {
+		g.Gopher.Slip, g.Gopher.slipV = -maxSlip, 0
+	}
+}
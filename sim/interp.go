@@ -0,0 +1,27 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sim
+
+// The simulation runs at the clock rate, but the screen may be
+// redrawn more often. Rather than show the same frame until the
+// next is calculated, the scene draws the gopher and the scroll
+// between the last two frames, by how far time has moved on.
+
+// A Snapshot is where things moving smoothly were after a frame.
+type Snapshot struct {
+	ScrollX float32
+	GopherX float32
+	GopherY float32
+}
+
+func (g *Game) snapshot() Snapshot {
+	return Snapshot{g.Scroll.X, g.Gopher.Slip, g.Gopher.Y}
+}
+
+// Interpolate tells the game how far, from 0 to 1, the time drawn
+// is between the last frame calculated by Update and the next.
+func (g *Game) Interpolate(f float32) {
+	g.Frac = f
+}
@@ -0,0 +1,61 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sim
+
+const (
+	hopV          = 0.6 // fraction of the jump velocity a jump starts with
+	jumpBoostTime = 8   // how long holding the button keeps a jump rising harder
+	jumpBuffer    = 6   // how long before landing a jump may be pressed, and still jump on landing
+	coyoteTime    = 6   // how long after running off an edge the gopher may still jump
+)
+
+// jump makes the gopher jump from the ground. The jump starts as a short hop,
+// and is boosted towards a full jump for as long as the button is held,
+// up to jumpBoostTime.
+func (g *Game) jump() {
+	g.Gopher.V = g.jumpV * hopV
+	g.Gopher.jumped = g.LastCalc
+	g.Gopher.boosting = true
+	g.stretchGopher(jumpStretch)
+	g.comboJump()
+	g.event(eventJump)
+	g.sound(SfxJump)
+}
+
+// boostJump keeps the gopher's jump rising harder while the button is held.
+func (g *Game) boostJump() {
+	if !g.Gopher.boosting {
+		return
+	}
+	if !g.Gopher.held || g.Gopher.V >= 0 || g.LastCalc-g.Gopher.jumped >= jumpBoostTime {
+		g.Gopher.boosting = false
+		return
+	}
+	g.Gopher.V += g.jumpV * (1 - hopV) * ClockRate / jumpBoostTime * g.dt()
+}
+
+// coyote reports whether the gopher ran off an edge, rather than jumping,
+// recently enough that it may still jump.
+func (g *Game) coyote() bool {
+	gp := &g.Gopher
+	return !gp.AtRest && gp.flaps == 0 && gp.V >= 0 &&
+		gp.jumped < gp.grounded && g.LastCalc-gp.grounded <= coyoteTime
+}
+
+// bufferJump remembers a jump pressed in mid-air that did nothing,
+// so that the gopher jumps if it lands soon after.
+func (g *Game) bufferJump() {
+	g.Gopher.buffered = true
+	g.Gopher.pressed = g.LastCalc
+}
+
+// jumpBuffered makes the gopher, which has just landed,
+// jump if a jump was pressed shortly before.
+func (g *Game) jumpBuffered() {
+	if g.Gopher.buffered && g.LastCalc-g.Gopher.pressed <= jumpBuffer {
+		g.jump()
+	}
+	g.Gopher.buffered = false
+}
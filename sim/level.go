@@ -0,0 +1,92 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sim
+
+import "strconv"
+
+const (
+	xpPerMetres   = 10             // metres run for each point of XP
+	xpPerCoin     = 2              // XP for each coin collected
+	xpPerNearMiss = 5              // XP for each near miss
+	nearMissGap   = TileHeight / 2 // how close the gopher must pass something for a near miss
+	levelUpTime   = ClockRate * 3  // how long a level-up is announced for
+)
+
+// levelXP returns the XP needed to reach level n.
+// Each level takes 100 XP more than the last.
+func levelXP(n int) int {
+	return 50 * n * (n - 1)
+}
+
+// Level returns the player's level, from 1 up.
+func (g *Game) Level() int {
+	n := 1
+	for g.saved.XP >= levelXP(n+1) {
+		n++
+	}
+	return n
+}
+
+// A Cosmetic is worn by the gopher once the player reaches its level.
+type Cosmetic struct {
+	level int    // level at which the cosmetic is unlocked
+	name  string // what the player is told
+	Tex   int    // texture drawn on the gopher's head
+}
+
+var cosmetics = []Cosmetic{
+	{3, "Cap", TexCap},
+	{6, "Top hat", TexTopHat},
+	{10, "Crown", TexCrown},
+}
+
+// Hat returns the best cosmetic the player has unlocked.
+func (g *Game) Hat() (c Cosmetic, ok bool) {
+	level := g.Level()
+	for _, x := range cosmetics {
+		if x.level <= level {
+			c, ok = x, true
+		}
+	}
+	return c, ok
+}
+
+// runXP returns the XP earned this run.
+func (g *Game) runXP() int {
+	return g.Distance()/xpPerMetres + g.Collected*xpPerCoin + g.nearMisses*xpPerNearMiss
+}
+
+// awardXP pays the XP earned this run that hasn't already been paid,
+// and announces any level-up.
+func (g *Game) awardXP() {
+	level := g.Level()
+	xp := g.runXP()
+	g.saved.XP += xp - g.xpPaid
+	g.xpPaid = xp
+	if g.Level() > level {
+		g.levelUp = g.LastCalc
+	}
+}
+
+// nearMiss records whether the gopher only just got past an obstacle
+// spanning y0 to y1, which it has passed.
+func (g *Game) nearMiss(y0, y1 float32) {
+	_, gy0, _, gy1 := g.GopherBounds()
+	gap := y0 - gy1
+	if d := gy0 - y1; d > gap {
+		gap = d
+	}
+	if gap < nearMissGap {
+		g.nearMisses++
+	}
+}
+
+// LevelBanner returns the level-up announcement, if there is one.
+func (g *Game) LevelBanner() string {
+	if g.levelUp < 0 || g.LastCalc-g.levelUp > levelUpTime {
+		return ""
+	}
+	return "LEVEL " + strconv.Itoa(g.Level())
+}
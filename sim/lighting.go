@@ -0,0 +1,40 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sim
+
+const (
+	LightLevels  = 4             // number of shades of darkness
+	LightRadius  = TileWidth * 4 // how far the light around the gopher reaches
+	nightDark    = 0.5           // how dark it is at night
+	caveDark     = 0.85          // how dark it is in caves
+	darkenSpeed  = 0.6           // how quickly it gets lighter or darker, per second
+	LightFalloff = 0.35          // fraction of the light's radius that is fully lit
+)
+
+// calcLighting brings the darkness closer to how dark it should be
+// where the gopher is: dark at night, and darker still in caves.
+func (g *Game) calcLighting() {
+	want := float32(0)
+	if g.IsNight() {
+		want = nightDark
+	}
+	if g.GroundBiome[GopherTile] == biomeCave {
+		want = caveDark
+	}
+	step := darkenSpeed * g.dt()
+	switch {
+	case g.dark < want-step:
+		g.dark += step
+	case g.dark > want+step:
+		g.dark -= step
+	default:
+		g.dark = want
+	}
+}
+
+// LightLevel returns the shade of darkness to draw, or 0 for none.
+func (g *Game) LightLevel() int {
+	return int(g.dark*LightLevels + 0.5)
+}
@@ -0,0 +1,137 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sim
+
+const activeMissions = 3 // number of missions the player has at once
+
+// A gameEvent is something the gopher does that missions may count.
+type gameEvent int
+
+const (
+	eventNone  gameEvent = iota
+	eventJump            // the gopher jumped off the ground
+	eventFlap            // the gopher flapped in mid-air
+	eventCoin            // the gopher collected a coin
+	eventMetre           // the gopher ran another tile
+	eventDeath           // the gopher died
+	eventClimb           // the gopher climbed a cliff
+)
+
+// A missionTemplate describes a mission that may be set for the player.
+type missionTemplate struct {
+	name    string    // what the player must do
+	goal    int       // how many events must be counted
+	reward  int       // coins paid when the mission is complete
+	counts  gameEvent // event that counts towards the goal
+	resetOn gameEvent // event that resets the count, if any
+	perRun  bool      // must the goal be reached in a single run?
+}
+
+var missionTemplates = []missionTemplate{
+	{name: "Jump 50 times", goal: 50, reward: 50, counts: eventJump},
+	{name: "Collect 30 coins in one run", goal: 30, reward: 40, counts: eventCoin, perRun: true},
+	{name: "Reach 1000m without flapping", goal: 1000, reward: 100, counts: eventMetre, resetOn: eventFlap, perRun: true},
+	{name: "Flap 100 times", goal: 100, reward: 30, counts: eventFlap},
+	{name: "Run 5000m in total", goal: 5000, reward: 100, counts: eventMetre},
+	{name: "Jump 20 times in one run", goal: 20, reward: 40, counts: eventJump, perRun: true},
+	{name: "Collect 200 coins", goal: 200, reward: 60, counts: eventCoin},
+	{name: "Reach 500m in one run", goal: 500, reward: 50, counts: eventMetre, perRun: true},
+}
+
+// A mission is a mission set for the player, as it is saved.
+type mission struct {
+	ID       int // index into missionTemplates
+	Progress int // events counted so far
+}
+
+// A Mission is a task set for the player, rewarded with coins.
+type Mission struct {
+	Name     string // what the player must do
+	Progress int    // how far the player has got
+	Goal     int    // how far the player must get
+	Reward   int    // coins paid on completion
+}
+
+// Missions returns the player's current missions.
+func (g *Game) Missions() []Mission {
+	var ms []Mission
+	for _, m := range g.saved.Missions {
+		t := &missionTemplates[m.ID]
+		ms = append(ms, Mission{t.name, m.Progress, t.goal, t.reward})
+	}
+	return ms
+}
+
+// dealMissions tops up the player's missions,
+// dropping any saved missions that no longer exist.
+func (g *Game) dealMissions() {
+	ms := g.saved.Missions[:0]
+	for _, m := range g.saved.Missions {
+		if m.ID >= 0 && m.ID < len(missionTemplates) {
+			ms = append(ms, m)
+		}
+	}
+	g.saved.Missions = ms
+	for len(g.saved.Missions) < activeMissions {
+		g.saved.Missions = append(g.saved.Missions, g.nextMission())
+	}
+}
+
+// nextMission returns the next mission that isn't already set.
+func (g *Game) nextMission() mission {
+	for {
+		id := g.saved.NextMission % len(missionTemplates)
+		g.saved.NextMission++
+		if !g.hasMission(id) {
+			return mission{ID: id}
+		}
+	}
+}
+
+func (g *Game) hasMission(id int) bool {
+	for _, m := range g.saved.Missions {
+		if m.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// event counts e towards the player's missions and achievements,
+// paying out for and replacing any missions that are complete.
+func (g *Game) event(e gameEvent) {
+	if g.replaying {
+		return
+	}
+	done := g.countAchievements(e)
+	for i := range g.saved.Missions {
+		m := &g.saved.Missions[i]
+		t := &missionTemplates[m.ID]
+		switch e {
+		case t.counts:
+			m.Progress++
+		case t.resetOn:
+			m.Progress = 0
+		}
+		if m.Progress >= t.goal {
+			g.saved.Coins += t.reward
+			*m = g.nextMission()
+			done = true
+		}
+	}
+	if done {
+		g.saveProgress()
+	}
+}
+
+// endRunMissions resets the missions that must be completed in a single run.
+func (g *Game) endRunMissions() {
+	for i := range g.saved.Missions {
+		m := &g.saved.Missions[i]
+		if missionTemplates[m.ID].perRun {
+			m.Progress = 0
+		}
+	}
+}
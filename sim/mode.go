@@ -0,0 +1,169 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sim
+
+import (
+	"fmt"
+
+	"golang.org/x/mobile/exp/sprite/clock"
+)
+
+// A Mode is a set of rules for the game.
+type Mode int
+
+const (
+	Endless    Mode = iota // run as far as possible
+	Sprint                 // run a fixed course as fast as possible
+	Survival               // run as far as possible as hazards mount, without power-ups
+	TimeAttack             // race past markers, against the fastest splits
+	Zen                    // run without dying, for practice; nothing is recorded
+	numModes
+)
+
+var modeNames = [numModes]string{"Endless", "Sprint", "Survival", "Time attack", "Zen"}
+
+func (m Mode) String() string {
+	if m < 0 || m >= numModes {
+		return fmt.Sprintf("Mode(%d)", int(m))
+	}
+	return modeNames[m]
+}
+
+// SetMode changes the rules of the game, starting a new run.
+func (g *Game) SetMode(m Mode) {
+	g.Mode = m
+	g.reset()
+}
+
+// CycleMode changes to the next mode, from the character select screen.
+func (g *Game) CycleMode() {
+	if g.state == StateMenu {
+		g.SetMode((g.Mode + 1) % numModes)
+	}
+}
+
+const (
+	sprintLength = 2000 // length of the sprint course
+	survivalRamp = 500  // distance over which survival hazards become twice as likely
+)
+
+// escalate returns the 1/probability p of a hazard appearing,
+// made more likely with distance in survival mode.
+func (g *Game) escalate(p int) int {
+	if g.Mode != Survival {
+		return p
+	}
+	p = p * survivalRamp / (survivalRamp + g.Distance())
+	if p < 1 {
+		p = 1
+	}
+	return p
+}
+
+// Multiplier returns the score multiplier.
+// Survival mode only counts distance.
+func (g *Game) Multiplier() int {
+	if g.Mode == Survival {
+		return 1
+	}
+	return g.Combo.multiplier()
+}
+
+// calcMode times the run and ends the sprint at the finish line.
+func (g *Game) calcMode() {
+	if g.Over() {
+		return
+	}
+	g.runTime++
+	switch g.Mode {
+	case Sprint:
+		if g.Distance() >= sprintLength {
+			g.finishRun()
+		}
+	case TimeAttack:
+		g.calcSplits()
+	}
+}
+
+// timed reports whether the run is timed from the start:
+// a sprint, or a time attack, whose splits are all taken in one go.
+func (g *Game) timed() bool {
+	return g.Mode == Sprint || g.Mode == TimeAttack
+}
+
+// Finished reports whether the gopher has crossed the sprint's finish line.
+func (g *Game) Finished() bool {
+	return g.Over() && g.Gopher.cause == causeFinish
+}
+
+// finishRun ends the sprint, with the gopher running on
+// as the ground slows to a stop.
+func (g *Game) finishRun() {
+	g.setState(StateDead)
+	g.Gopher.DeadTime = g.LastCalc
+	g.Gopher.cause = causeFinish
+	g.endRun()
+}
+
+// recordBest records the score of the run just ended.
+func (g *Game) recordBest() {
+	switch g.Mode {
+	case Sprint:
+		if g.Finished() && (g.saved.SprintBest == 0 || g.runTime < g.saved.SprintBest) {
+			g.saved.SprintBest = g.runTime
+		}
+	case Zen:
+		// Practice runs don't count.
+	case TimeAttack:
+		g.recordSplits()
+	case Survival:
+		if s := g.Score(); s > g.saved.SurvivalBest {
+			g.saved.SurvivalBest = s
+		}
+	default:
+		s := g.Score()
+		if g.daily {
+			if s > g.dailyBest() {
+				g.saved.DailyBest = s
+				g.saved.DailyDate = today()
+			}
+		} else if s > g.saved.Best {
+			g.saved.Best = s
+		}
+	}
+}
+
+// ScoreText returns the score shown while running:
+// the time in sprint and time attack modes,
+// and the distance score otherwise.
+func (g *Game) ScoreText() string {
+	if g.Mode == Sprint || g.Mode == TimeAttack {
+		return formatTime(g.runTime)
+	}
+	return fmt.Sprint(g.Score())
+}
+
+// BestText returns the best score for the mode, shown when the run ends.
+func (g *Game) BestText() string {
+	switch {
+	case g.Mode == Sprint:
+		return "BEST " + formatTime(g.saved.SprintBest)
+	case g.Mode == TimeAttack:
+		if n := len(g.saved.BestSplits); n > 0 {
+			return "BEST " + formatTime(g.saved.BestSplits[n-1])
+		}
+		return "BEST 0.00"
+	case g.Mode == Survival:
+		return fmt.Sprint("BEST ", g.saved.SurvivalBest)
+	case g.daily:
+		return fmt.Sprint("DAILY ", g.dailyBest())
+	}
+	return fmt.Sprint("BEST ", g.saved.Best)
+}
+
+// formatTime formats t in seconds, to hundredths.
+func formatTime(t clock.Time) string {
+	return fmt.Sprintf("%d.%02d", t/ClockRate, t%ClockRate*100/ClockRate)
+}
@@ -0,0 +1,111 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sim
+
+const (
+	maxObstacles  = 4                  // maximum number of obstacles at once
+	obstacleProb  = 6                  // 1/probability of a new tile having an obstacle
+	obstacleGap   = 6                  // minimum number of tiles between obstacles
+	obstacleStart = TilesX * 2         // distance the gopher runs before obstacles appear
+	pipeGap       = TileHeight * 3     // space between the ground and a pipe
+	lowPipeGap    = TileHeight * 3 / 4 // space between the ground and a low pipe
+	logV          = -30                // velocity of rolling logs
+	rockW, rockH  = TileWidth, TileHeight * 3 / 4
+	logW, logH    = TileWidth, TileHeight * 3 / 4
+	pipeW         = TileWidth
+)
+
+// Kinds of obstacle.
+const (
+	obstacleRock    = iota
+	obstacleLog     // rolls towards the gopher
+	obstaclePipe    // hangs down from the top of the screen
+	obstacleLowPipe // hangs low enough that the gopher must slide
+)
+
+// spawnObstacle maybe places an obstacle on the last ground tile.
+func (g *Game) spawnObstacle() {
+	if g.Travelled < obstacleStart*TileWidth || g.countEntities(EntityObstacle) >= maxObstacles || g.BossActive() {
+		return
+	}
+	last := g.lastTile()
+	x := float32(last * TileWidth)
+	if o := g.lastEntity(EntityObstacle); o != nil && o.X > x-obstacleGap*TileWidth {
+		return
+	}
+	if g.rng.Intn(g.escalate(obstacleProb)) != 0 || g.isPit(last) || g.GroundType[last] != tileNormal || g.groundSlope[last] {
+		return
+	}
+	ground := g.GroundY[last]
+	var o Entity
+	kinds := Biomes[g.GroundBiome[last]].obstacles
+	switch kinds[g.rng.Intn(len(kinds))] {
+	case obstacleRock:
+		o = newObstacle(x, ground, rockW, rockH, 0, TexRock)
+	case obstacleLog:
+		o = newObstacle(x, ground, logW, logH, logV, TexLog)
+	case obstaclePipe:
+		o = newPipe(x, ground-pipeGap)
+	case obstacleLowPipe:
+		o = newPipe(x, ground-lowPipeGap)
+	}
+	g.addEntity(o)
+	g.soundAt(SfxObstacle, x)
+}
+
+// newObstacle returns an obstacle of size w by h, with texture tex,
+// resting on the ground at x and y and rolling at velocity v.
+func newObstacle(x, y, w, h, v float32, tex int) Entity {
+	has := hasGround | hasCollider | hasRenderable
+	if v != 0 {
+		has |= hasVelocity
+	}
+	return Entity{
+		Kind:       EntityObstacle,
+		has:        has,
+		position:   position{x, y - h, w, h},
+		velocity:   velocity{vx: v},
+		collider:   collider{touches: touchHarm},
+		renderable: renderable{Tex: tex, Layer: LayerObstacles},
+	}
+}
+
+// newPipe returns a pipe hanging down from the top of the world to y, at x.
+func newPipe(x, y float32) Entity {
+	return Entity{
+		Kind:       EntityObstacle,
+		has:        hasCollider | hasRenderable,
+		position:   position{x, 0, pipeW, y},
+		collider:   collider{touches: touchHarm},
+		renderable: renderable{Tex: TexPipe, Layer: LayerObstacles, Hang: true},
+	}
+}
+
+// calcObstacles records the obstacles the gopher has got past.
+func (g *Game) calcObstacles() {
+	if g.Over() {
+		return
+	}
+	x0, _, _, _ := g.GopherBounds()
+	for i := range g.Entities {
+		o := &g.Entities[i]
+		if o.Kind == EntityObstacle && o.has&hasCollider != 0 && !o.passed && o.X+o.W < x0 {
+			o.passed = true
+			g.nearMiss(o.Y, o.Y+o.H)
+		}
+	}
+}
+
+// GroundAt returns the ground y-offset at x, relative to the ground tiles.
+func (g *Game) GroundAt(x float32) float32 {
+	i := int(x / TileWidth)
+	if i < 0 {
+		i = 0
+	}
+	if i >= g.Tiles {
+		i = g.lastTile()
+	}
+	return g.slopeAt(i, x)
+}
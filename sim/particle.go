@@ -0,0 +1,50 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sim
+
+import (
+	"math"
+
+	"golang.org/x/mobile/exp/sprite/clock"
+)
+
+const maxParticles = 48 // most particles at once
+
+// A burst describes a kind of particle emission.
+type burst struct {
+	tex     int
+	n       int     // number of particles
+	speed   float32 // maximum initial speed
+	up      float32 // added upward velocity
+	gravity float32
+	size    float32
+	life    clock.Time
+}
+
+var (
+	dustBurst    = burst{TexDust, 6, 48, 18, 36, TileWidth / 2, 24}
+	debrisBurst  = burst{TexDebris, 8, 120, 90, 360, TileWidth / 3, 60}
+	featherBurst = burst{TexFeather, 10, 90, 60, 45, TileWidth / 2, 120}
+)
+
+// emit sends out a burst of short-lived particles from x, y,
+// replacing the oldest particles if there are too many.
+func (g *Game) emit(b burst, x, y float32) {
+	for i := 0; i < b.n; i++ {
+		if g.countEntities(EntityParticle) >= maxParticles {
+			removeEntity(g.firstEntity(EntityParticle))
+		}
+		a := g.rng.Float64() * 2 * math.Pi
+		s := b.speed * g.rng.Float32()
+		g.addEntity(Entity{
+			Kind:       EntityParticle,
+			has:        hasVelocity | hasLife | hasRenderable,
+			position:   position{x - b.size/2, y - b.size/2, b.size, b.size},
+			velocity:   velocity{s * float32(math.Cos(a)), s*float32(math.Sin(a)) - b.up, b.gravity},
+			life:       life{g.LastCalc, b.life},
+			renderable: renderable{Tex: b.tex, Layer: LayerParticles},
+		})
+	}
+}
@@ -0,0 +1,80 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sim
+
+// Pause menu options.
+const (
+	pauseResume = iota
+	pauseRestart
+	pauseQuit
+	numPauseOptions
+)
+
+var pauseOptions = [numPauseOptions]string{"RESUME", "RESTART", "QUIT"}
+
+// Pause pauses or resumes the game.
+// Only a run in progress may be paused.
+//
+// Update doesn't advance the game while it is paused,
+// so the caller should hold its clock still until it is resumed.
+func (g *Game) Pause() {
+	if g.state == StatePaused {
+		g.setState(StatePlaying)
+		return
+	}
+	if g.state != StatePlaying || g.Fade.Active {
+		return
+	}
+	g.setState(StatePaused)
+}
+
+// letGo lets go of the buttons held down when the game is paused.
+func (g *Game) letGo() {
+	if !g.takeInput(inputLetGo, false, 0) {
+		return
+	}
+	g.Gopher.held = false
+	g.Gopher.Sliding = false
+}
+
+// Paused reports whether the game is paused.
+func (g *Game) Paused() bool {
+	return g.state == StatePaused
+}
+
+// choosePauseOption does what the selected pause menu option says.
+func (g *Game) choosePauseOption() {
+	g.setState(StatePlaying)
+	wasReplay := g.replaying
+	if g.pauseItem != pauseResume {
+		// Leaving a replay goes back to playing.
+		g.replaying = false
+	}
+	switch g.pauseItem {
+	case pauseRestart:
+		// Bank what the player has earned, and run again.
+		// A replay's run was banked when it was played.
+		if !wasReplay {
+			g.endRun()
+		}
+		g.cutTo(g.reset)
+	case pauseQuit:
+		if !wasReplay {
+			g.endRun()
+		}
+		g.cutTo(func() {
+			g.reset()
+			g.setState(StateMenu)
+		})
+	}
+}
+
+// PausePrompt returns the pause menu option being shown.
+func (g *Game) PausePrompt() string {
+	if g.state != StatePaused {
+		return ""
+	}
+	return "< " + pauseOptions[g.pauseItem] + " >"
+}
@@ -0,0 +1,73 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sim
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+)
+
+// physics are the parameters of how the gopher moves,
+// which may be tweaked in physics.json without rebuilding the game.
+// Velocities are in pixels per second, and accelerations
+// in pixels per second per second.
+type physics struct {
+	Gravity     float32 // gravity
+	JumpV       float32 // jump velocity
+	FlapV       float32 // flap velocity
+	DeadScrollA float32 // scroll decelleration after the gopher dies
+	ClimbGrace  float32 // gopher won't die if it hits a cliff this high
+}
+
+// defaultPhysics are used for any parameters physics.json doesn't give,
+// or for all of them if it can't be loaded.
+var defaultPhysics = physics{
+	Gravity:     360,
+	JumpV:       -300,
+	FlapV:       -90,
+	DeadScrollA: -36,
+	ClimbGrace:  TileHeight / 3,
+}
+
+// loadPhysics reads the physics parameters from physics.json, opened
+// with open, falling back to the defaults if it is missing or invalid.
+func loadPhysics(open func(string) (io.ReadCloser, error)) physics {
+	if open == nil {
+		return defaultPhysics
+	}
+	a, err := open("physics.json")
+	if err != nil {
+		log.Print(err)
+		return defaultPhysics
+	}
+	defer a.Close()
+
+	p := defaultPhysics
+	if err := json.NewDecoder(a).Decode(&p); err != nil {
+		log.Printf("physics.json: %v", err)
+		return defaultPhysics
+	}
+	if err := p.validate(); err != nil {
+		log.Printf("physics.json: %v", err)
+		return defaultPhysics
+	}
+	return p
+}
+
+func (p physics) validate() error {
+	switch {
+	case p.Gravity <= 0:
+		return fmt.Errorf("gravity must pull downwards")
+	case p.JumpV >= 0 || p.FlapV >= 0:
+		return fmt.Errorf("jumps and flaps must go upwards")
+	case p.DeadScrollA >= 0:
+		return fmt.Errorf("scrolling must slow down after the gopher dies")
+	case p.ClimbGrace < 0 || p.ClimbGrace >= TileHeight:
+		return fmt.Errorf("climb grace must be from 0 to less than a tile")
+	}
+	return nil
+}
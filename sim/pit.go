@@ -0,0 +1,66 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sim
+
+const (
+	pitY     = TileHeight * TilesY * 2 // ground y-offset of a pit, well below the screen
+	pitProb  = 20                      // 1/probability of a new tile starting a pit
+	pitStart = TilesX * 3              // distance the gopher runs before pits appear
+	minPit   = 2                       // minimum width of a pit, in tiles
+	maxPit   = 3                       // maximum width of a pit, in tiles
+)
+
+// nextPitY returns the ground y-offset of the next tile
+// if it is part of a pit or the ground just beyond one.
+func (g *Game) nextPitY() (y float32, ok bool) {
+	prev := g.GroundY[g.lastTile()]
+	switch {
+	case g.pitLeft > 0:
+		g.pitLeft--
+		return pitY, true
+	case prev == pitY:
+		// The far side of a pit is as high as the near side.
+		return g.pitEdge, true
+	case g.Travelled >= pitStart*TileWidth && g.rng.Intn(g.escalate(pitProb)) == 0:
+		g.pitEdge = prev
+		g.pitLeft = minPit + g.rng.Intn(maxPit-minPit+1) - 1
+		return pitY, true
+	}
+	return 0, false
+}
+
+// isPit reports whether ground tile i is part of a pit.
+func (g *Game) isPit(i int) bool {
+	return g.GroundY[i] == pitY
+}
+
+// surfaceY returns the y-offset of ground tile i,
+// or of the ground beside it if it is part of a pit,
+// or of the water if it is part of a pool.
+func (g *Game) surfaceY(i int) float32 {
+	if g.isPit(i) {
+		return g.pitEdge
+	}
+	if g.GroundType[i] == TileWater {
+		return g.WaterY(i)
+	}
+	return g.GroundY[i]
+}
+
+// gopherFell reports whether the gopher has fallen into a pit.
+func (g *Game) gopherFell() bool {
+	return g.Gopher.Y > TileHeight*TilesY
+}
+
+// fillPits fills in the pits in front of the gopher,
+// so that a gopher that survives falling in can carry on.
+func (g *Game) fillPits() {
+	for i := range g.GroundY {
+		if g.isPit(i) {
+			g.GroundY[i] = g.pitEdge
+		}
+	}
+	g.pitLeft = 0
+}
@@ -0,0 +1,64 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sim
+
+import "math"
+
+const (
+	maxPlatforms    = 3              // maximum number of platforms at once
+	platformProb    = 12             // 1/probability of a new tile having a platform
+	platformW       = TileWidth * 3  // width of a platform
+	platformH       = TileHeight / 2 // thickness of a platform
+	platformMinUp   = 3              // minimum height of a platform above the ground, in tiles
+	platformMaxUp   = 5              // maximum height of a platform above the ground, in tiles
+	platformAmp     = TileHeight / 2 // how far platforms bob up and down
+	platformPeriod  = 120            // how long it takes a platform to bob up and down
+	platformSnap    = 2              // how far above a platform the gopher can be to land on it
+	platformAirTime = TilesX / 2     // minimum distance between platforms, in tiles
+)
+
+// spawnPlatform maybe places a platform above the last ground tile.
+// Platforms float above the ground, bobbing up and down,
+// and the gopher can land on them from above.
+func (g *Game) spawnPlatform() {
+	if g.countEntities(EntityPlatform) >= maxPlatforms || g.rng.Intn(platformProb) != 0 {
+		return
+	}
+	last := g.lastTile()
+	x := float32(last * TileWidth)
+	if p := g.lastEntity(EntityPlatform); p != nil && p.X > x-platformAirTime*TileWidth {
+		return
+	}
+	up := float32(platformMinUp + g.rng.Intn(platformMaxUp-platformMinUp+1))
+	y := g.surfaceY(last) - up*TileHeight
+	g.addEntity(Entity{
+		Kind:       EntityPlatform,
+		has:        hasPath | hasRenderable,
+		position:   position{x, y, platformW, platformH},
+		path:       path{route: pathBob, baseY: y, phase: g.rng.Float32() * 2 * math.Pi, origin: g.started},
+		renderable: renderable{Tex: TexPlatform, Layer: LayerPlatforms},
+	})
+}
+
+// platformBelow returns the y-offset of the top of the platform that the
+// gopher is standing on or falling onto, if any.
+func (g *Game) platformBelow() (y float32, ok bool) {
+	if g.Gopher.V < 0 {
+		// Gopher jumps through platforms from below.
+		return 0, false
+	}
+	b := g.gopherBox()
+	prev := b.y1 - g.Gopher.V*g.dt() // where the gopher's feet were
+	for i := range g.Entities {
+		p := &g.Entities[i]
+		if p.Kind != EntityPlatform || p.has == 0 || !b.across(p.box()) || prev > p.Y+platformSnap {
+			continue
+		}
+		if !ok || p.Y < y {
+			y, ok = p.Y, true
+		}
+	}
+	return y, ok
+}
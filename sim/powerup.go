@@ -0,0 +1,206 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sim
+
+import "golang.org/x/mobile/exp/sprite/clock"
+
+const (
+	maxPickups   = 2              // maximum number of power-up pickups at once
+	pickupProb   = 40             // 1/probability of a new tile having a pickup
+	pickupSize   = TileWidth      // width and height of a pickup
+	pickupUp     = 3              // height of a pickup above the ground, in tiles
+	ExpiryWarned = 120            // how long the indicator blinks before expiry
+	superJumpA   = 1.4            // jump velocity multiplier of the super jump
+	superJumpT   = ClockRate * 10 // duration of the super jump
+	doubleCoinsT = ClockRate * 15 // duration of double coins
+	magnetT      = ClockRate * 12 // duration of the coin magnet
+	shieldT      = ClockRate * 30 // how long the shield lasts if it isn't broken
+	slowMoT      = ClockRate * 5  // duration of slow motion
+	slowMoScale  = 0.5            // simulation rate during slow motion
+	ShatterTime  = 30             // how long the pieces of a broken shield fly
+	Shards       = 6              // number of pieces a shield breaks into
+	ShardV       = 120            // velocity of the pieces of a broken shield
+)
+
+// A PowerUp temporarily changes the rules of the game
+// after the gopher collects it.
+type PowerUp interface {
+	// Apply is called when the gopher collects the power-up.
+	Apply(g *Game)
+	// Expire is called when the power-up wears off.
+	// It should undo the effects of Apply.
+	Expire(g *Game)
+	// Duration reports how long the power-up lasts.
+	Duration() clock.Time
+
+	// Tex returns the texture used for the pickup and indicator.
+	Tex() int
+	// id returns the identifier under which upgrades are saved.
+	id() string
+	// name returns what the player is told the power-up is.
+	name() string
+}
+
+// powerUps lists the power-ups that may be spawned.
+var powerUps = []PowerUp{
+	superJump{},
+	doubleCoins{},
+	magnet{},
+	shield{},
+	slowMo{},
+	star{},
+}
+
+// superJump makes the gopher jump higher.
+type superJump struct{}
+
+func (superJump) Apply(g *Game)        { g.jumpV *= superJumpA }
+func (superJump) Expire(g *Game)       { g.jumpV /= superJumpA }
+func (superJump) Duration() clock.Time { return superJumpT }
+func (superJump) Tex() int             { return TexSuperJump }
+func (superJump) id() string           { return "superjump" }
+func (superJump) name() string         { return "super jump" }
+
+// doubleCoins makes each coin worth two.
+type doubleCoins struct{}
+
+func (doubleCoins) Apply(g *Game)        { g.coinValue *= 2 }
+func (doubleCoins) Expire(g *Game)       { g.coinValue /= 2 }
+func (doubleCoins) Duration() clock.Time { return doubleCoinsT }
+func (doubleCoins) Tex() int             { return TexDoubleCoins }
+func (doubleCoins) id() string           { return "doublecoins" }
+func (doubleCoins) name() string         { return "double coins" }
+
+// magnet draws nearby coins towards the gopher.
+type magnet struct{}
+
+func (magnet) Apply(g *Game)        { g.Magnetised = true }
+func (magnet) Expire(g *Game)       { g.Magnetised = false }
+func (magnet) Duration() clock.Time { return magnetT }
+func (magnet) Tex() int             { return TexMagnet }
+func (magnet) id() string           { return "magnet" }
+func (magnet) name() string         { return "magnet" }
+
+// shield absorbs the next crash.
+type shield struct{}
+
+func (shield) Apply(g *Game)        { g.Gopher.Shielded = true }
+func (shield) Expire(g *Game)       { g.Gopher.Shielded = false }
+func (shield) Duration() clock.Time { return shieldT }
+func (shield) Tex() int             { return TexShield }
+func (shield) id() string           { return "shield" }
+func (shield) name() string         { return "shield" }
+
+// slowMo slows the game down, for threading difficult terrain.
+type slowMo struct{}
+
+func (slowMo) Apply(g *Game)        { g.timeScale = slowMoScale }
+func (slowMo) Expire(g *Game)       { g.timeScale = 1 }
+func (slowMo) Duration() clock.Time { return slowMoT }
+func (slowMo) Tex() int             { return TexSlowMo }
+func (slowMo) id() string           { return "slowmo" }
+func (slowMo) name() string         { return "slow motion" }
+
+// star makes the gopher invincible,
+// and lets it smash through obstacles.
+type star struct{}
+
+func (star) Apply(g *Game)        { g.Gopher.Starred = true }
+func (star) Expire(g *Game)       { g.Gopher.Starred = false }
+func (star) Duration() clock.Time { return starT }
+func (star) Tex() int             { return TexStar }
+func (star) id() string           { return "star" }
+func (star) name() string         { return "star" }
+
+// An ActivePowerUp is a power-up that has been applied to the gopher.
+type ActivePowerUp struct {
+	P     PowerUp
+	Until clock.Time // when the power-up expires
+}
+
+// spawnPickup maybe places a power-up above the last ground tile.
+func (g *Game) spawnPickup() {
+	if g.Mode == Survival || g.countEntities(EntityPickup) >= maxPickups || g.rng.Intn(pickupProb) != 0 {
+		return
+	}
+	last := g.lastTile()
+	x := float32(last * TileWidth)
+	if o := g.lastEntity(EntityObstacle); o != nil && o.X == x {
+		return
+	}
+	if c := g.lastEntity(EntityCoin); c != nil && c.X >= x {
+		return
+	}
+	g.addEntity(newPickup(x, g.surfaceY(last)-pickupUp*TileHeight, powerUps[g.rng.Intn(len(powerUps))]))
+}
+
+// newPickup returns a pickup of p floating at x and y.
+func newPickup(x, y float32, p PowerUp) Entity {
+	return Entity{
+		Kind:       EntityPickup,
+		has:        hasCollider | hasRenderable,
+		position:   position{x, y, pickupSize, pickupSize},
+		collider:   collider{touches: touchCollect},
+		renderable: renderable{Tex: p.Tex(), Layer: LayerItems},
+		P:          p,
+	}
+}
+
+// applyPowerUp applies p to the gopher.
+// Collecting a power-up that is already active extends it.
+func (g *Game) applyPowerUp(p PowerUp) {
+	applied := false
+	for i, a := range g.active {
+		if a.P == p {
+			// Move it to the end so that it is shown as the latest.
+			g.active = append(g.active[:i], g.active[i+1:]...)
+			applied = true
+			break
+		}
+	}
+	if !applied {
+		p.Apply(g)
+	}
+	g.active = append(g.active, ActivePowerUp{p, g.LastCalc + g.powerUpDuration(p)})
+}
+
+// removePowerUp expires p early.
+func (g *Game) removePowerUp(p PowerUp) {
+	for i, a := range g.active {
+		if a.P == p {
+			p.Expire(g)
+			g.active = append(g.active[:i], g.active[i+1:]...)
+			return
+		}
+	}
+}
+
+// breakShield uses up the gopher's shield to save it from a crash.
+func (g *Game) breakShield() {
+	g.removePowerUp(shield{})
+	g.Gopher.Shattered = g.LastCalc
+	g.Gopher.safeTime = g.LastCalc + invulnerableTime
+}
+
+// LatestPowerUp returns the most recently collected active power-up.
+func (g *Game) LatestPowerUp() (a ActivePowerUp, ok bool) {
+	if len(g.active) == 0 {
+		return ActivePowerUp{}, false
+	}
+	return g.active[len(g.active)-1], true
+}
+
+// calcPowerUps expires any power-ups that have worn off.
+func (g *Game) calcPowerUps() {
+	active := g.active[:0]
+	for _, a := range g.active {
+		if g.LastCalc >= a.Until {
+			a.P.Expire(g)
+			continue
+		}
+		active = append(active, a)
+	}
+	g.active = active
+}
@@ -0,0 +1,110 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sim
+
+import (
+	"math/rand"
+
+	"golang.org/x/mobile/exp/sprite/clock"
+)
+
+// A run is simulated the same way every time from the same seed and
+// the same inputs at the same times, so it can be watched again after it
+// ends by recording just those. Nothing that happens in a replay counts
+// towards the player's progress.
+
+const maxInputs = 100000 // most inputs a replay may hold
+
+// Inputs of a run.
+const (
+	inputPress = iota // the jump button pressed or released
+	inputSlide        // the slide button pressed or released
+	inputThrow        // an acorn thrown
+	inputDash         // a dash
+	inputTilt         // the device tilted
+	inputLetGo        // every button let go, on pausing
+)
+
+// An input is something the player did during a run.
+type input struct {
+	t    clock.Time // when, since the run started
+	kind int        // see inputPress and friends
+	down bool       // pressed, rather than released
+	tilt float32    // tilt, for inputTilt
+}
+
+// A replay is what is needed to simulate a run again.
+type replay struct {
+	seed   int64   // seed of the run's world
+	inputs []input // the player's inputs, in order
+	ok     bool    // can the run be replayed? Resumed and continued runs can't.
+}
+
+// runSeed returns the seed of a new run's world.
+func (g *Game) runSeed() int64 {
+	switch {
+	case g.daily:
+		// Every daily run goes through the same world.
+		return dailySeed()
+	case g.seeded:
+		return g.seed
+	}
+	return rand.Int63()
+}
+
+// takeInput records an input to the run in progress,
+// and reports whether the game should act on it.
+// While a replay is shown, only the replay's own inputs are acted on.
+func (g *Game) takeInput(kind int, down bool, tilt float32) bool {
+	switch {
+	case g.feeding:
+		return true
+	case g.replaying:
+		return false
+	}
+	if len(g.recording.inputs) >= maxInputs {
+		g.recording.ok = false
+		return true
+	}
+	g.recording.inputs = append(g.recording.inputs, input{g.LastCalc - g.started, kind, down, tilt})
+	return true
+}
+
+// startReplay starts the run just ended again, to be watched.
+func (g *Game) startReplay() {
+	g.replaying = true
+	g.reset()
+}
+
+// Replaying reports whether a replay of the last run is being shown.
+func (g *Game) Replaying() bool {
+	return g.replaying
+}
+
+// feedInputs makes the inputs of the replay due by now.
+func (g *Game) feedInputs() {
+	if !g.replaying {
+		return
+	}
+	g.feeding = true
+	for ins := g.recording.inputs; g.played < len(ins) && ins[g.played].t <= g.LastCalc-g.started; g.played++ {
+		in := ins[g.played]
+		switch in.kind {
+		case inputPress:
+			g.Press(in.down)
+		case inputSlide:
+			g.Slide(in.down)
+		case inputThrow:
+			g.Throw(in.down)
+		case inputDash:
+			g.Dash()
+		case inputTilt:
+			g.SetTilt(in.tilt)
+		case inputLetGo:
+			g.letGo()
+		}
+	}
+	g.feeding = false
+}
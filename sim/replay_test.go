@@ -0,0 +1,63 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sim_test
+
+import (
+	"testing"
+
+	"github.com/adg/game/sim"
+	"golang.org/x/mobile/exp/sprite/clock"
+)
+
+func TestSeedDeterminism(t *testing.T) {
+	g1 := sim.NewGame(sim.Endless, sim.WithSeed(42))
+	g2 := sim.NewGame(sim.Endless, sim.WithSeed(42))
+	now := start(t, g1, 0)
+	if now2 := start(t, g2, 0); now2 != now {
+		t.Fatalf("runs started at %d and %d", now, now2)
+	}
+	for end := now + sim.ClockRate*60; now < end; now++ {
+		for _, g := range []*sim.Game{g1, g2} {
+			switch now % 45 {
+			case 0:
+				g.Press(true)
+			case 12:
+				g.Press(false)
+			}
+			g.Update(now)
+		}
+		if g1.GroundY != g2.GroundY || g1.GroundType != g2.GroundType {
+			t.Fatalf("worlds differ at tick %d", now)
+		}
+		if g1.Over() != g2.Over() {
+			t.Fatalf("one run ended at tick %d, the other didn't", now)
+		}
+		if g1.Over() {
+			break
+		}
+	}
+}
+
+func TestReplayScore(t *testing.T) {
+	g := sim.NewGame(sim.Endless, sim.WithSeed(7))
+	now := play(t, g, start(t, g, 0))
+	score := g.Score()
+	now = step(t, g, now, func(clock.Time) bool { return g.State() == sim.StateResults })
+
+	// Watch the replay, without touching anything.
+	for i := 0; g.SummaryPrompt() != "< WATCH REPLAY >"; i++ {
+		if i == 10 {
+			t.Fatal("no replay to watch")
+		}
+		g.Cycle(+1)
+	}
+	g.Press(true)
+	g.Press(false)
+	now = step(t, g, now, func(clock.Time) bool { return g.Replaying() && g.State() == sim.StatePlaying })
+	step(t, g, now, func(clock.Time) bool { return g.Over() })
+	if got := g.Score(); got != score {
+		t.Errorf("replay scored %d, want %d", got, score)
+	}
+}
@@ -0,0 +1,282 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sim
+
+import (
+	"bytes"
+	"encoding/gob"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/mobile/exp/sprite/clock"
+)
+
+const (
+	runVersion = 15   // version of the saved run format
+	maxSaved   = 1000 // most entities of any kind a saved run may hold
+)
+
+// rngSource is a rand.Source whose state can be saved with a run,
+// so that a resumed run carries on through the same world.
+// It is SplitMix64.
+type rngSource struct {
+	state uint64
+}
+
+func (s *rngSource) Seed(seed int64) { s.state = uint64(seed) }
+func (s *rngSource) Int63() int64    { return int64(s.Uint64() >> 1) }
+
+func (s *rngSource) Uint64() uint64 {
+	s.state += 0x9e3779b97f4a7c15
+	z := s.state
+	z = (z ^ z>>30) * 0xbf58476d1ce4e5b9
+	z = (z ^ z>>27) * 0x94d049bb133111eb
+	return z ^ z>>31
+}
+
+// A runCodec writes, or reads back, each part of a run in turn.
+type runCodec struct {
+	enc *gob.Encoder // set when writing
+	dec *gob.Decoder // set when reading
+	err error        // the first error
+}
+
+func (c *runCodec) value(p interface{}) {
+	if c.err != nil {
+		return
+	}
+	if c.enc != nil {
+		c.err = c.enc.Encode(p)
+	} else {
+		c.err = c.dec.Decode(p)
+	}
+}
+
+// length writes n, or reads back a length, and returns it.
+func (c *runCodec) length(n int) int {
+	c.value(&n)
+	if c.err == nil && (n < 0 || n > maxSaved) {
+		c.err = fmt.Errorf("bad length %d", n)
+	}
+	if c.err != nil {
+		return 0
+	}
+	return n
+}
+
+// powerUp writes, or reads back, the power-up *p.
+func (c *runCodec) powerUp(p *PowerUp) {
+	var id string
+	if *p != nil {
+		id = (*p).id()
+	}
+	c.value(&id)
+	if c.dec == nil || c.err != nil {
+		return
+	}
+	for _, q := range powerUps {
+		if q.id() == id {
+			*p = q
+			return
+		}
+	}
+	c.err = fmt.Errorf("unknown power-up %q", id)
+}
+
+// codeRun passes the state of the run through c.
+// Leaves, which are just for show, aren't saved.
+func (g *Game) codeRun(c *runCodec) {
+	v := c.value
+	version := runVersion
+	v(&version)
+	if c.err == nil && version != runVersion {
+		c.err = fmt.Errorf("unknown version %d", version)
+	}
+
+	gp := &g.Gopher
+	for _, p := range []interface{}{
+		&g.state, &gp.Y, &gp.V, &gp.AtRest, &gp.flaps, &gp.DeadTime, &gp.cause,
+		&gp.held, &gp.Gliding, &gp.Sliding, &gp.Grab, &gp.grabTime, &gp.grabY,
+		&gp.slidTime, &gp.dashUntil, &gp.dashReady, &gp.Lives, &gp.safeTime,
+		&gp.drift, &gp.Shielded, &gp.Shattered, &gp.Starred, &gp.breath, &gp.Slip, &gp.slipV,
+		&gp.jumped, &gp.boosting, &gp.grounded, &gp.buffered, &gp.pressed,
+		&g.Scroll.X, &g.Scroll.V,
+		&g.GroundY, &g.GroundTex, &g.GroundType, &g.groundSlope, &g.GroundBiome, &g.Scenery, &g.Tiles, &g.pitLeft, &g.pitEdge, &g.poolLeft, &g.poolEdge,
+		&g.Collected, &g.coinValue, &g.Magnetised, &g.jumpV, &g.thrown,
+		&g.boss.state, &g.boss.since, &g.boss.X, &g.boss.next, &g.boss.end, &g.boss.attack,
+		&g.gust.x, &g.gust.y, &g.gust.start,
+		&g.Travelled, &g.points,
+		&g.Combo.jumps, &g.Combo.landed, &g.Combo.Raised,
+		&g.checkpoint.distance, &g.checkpoint.points, &g.checkpoint.scrollV,
+		&g.runTime, &g.nearMisses, &g.xpPaid, &g.maxFlaps, &g.Character, &g.Mode, &g.daily,
+		&g.src.state, &g.timeScale, &g.steps, &g.LastCalc, &g.started,
+		&g.stingUntil, &g.beatBest,
+	} {
+		v(p)
+	}
+
+	reading := c.dec != nil
+	n := c.length(len(g.Entities))
+	if reading {
+		g.Entities = make([]Entity, n)
+	}
+	for i := range g.Entities {
+		e := &g.Entities[i]
+		for _, f := range []interface{}{
+			&e.Kind, &e.has, &e.X, &e.Y, &e.W, &e.H, &e.vx, &e.vy, &e.gravity,
+			&e.route, &e.baseY, &e.phase, &e.origin, &e.touches, &e.passed, &e.born, &e.span,
+			&e.Tex, &e.Layer, &e.Tumble, &e.Flap, &e.Flipped, &e.Hang,
+		} {
+			v(f)
+		}
+		if e.Kind == EntityPickup {
+			c.powerUp(&e.P)
+		}
+	}
+	n = c.length(len(g.active))
+	if reading {
+		g.active = make([]ActivePowerUp, n)
+	}
+	for i := range g.active {
+		c.powerUp(&g.active[i].P)
+		v(&g.active[i].Until)
+	}
+	n = c.length(len(g.Zones))
+	if reading {
+		g.Zones = make([]Zone, n)
+	}
+	for i := range g.Zones {
+		z := &g.Zones[i]
+		for _, f := range []interface{}{&z.X0, &z.X1, &z.Y, &z.gravity} {
+			v(f)
+		}
+	}
+	n = c.length(len(g.splits))
+	if reading {
+		g.splits = make([]clock.Time, n)
+	}
+	for i := range g.splits {
+		v(&g.splits[i])
+	}
+}
+
+func runFile() string {
+	return filepath.Join(DataDir(), "run.gob")
+}
+
+// Save saves the player's progress and, if the gopher is running,
+// the run, so that the run may be resumed the next time the game starts.
+func (g *Game) Save() {
+	g.saveProgress()
+	if g.Over() || g.Choosing() || g.replaying {
+		if err := os.Remove(runFile()); err != nil && !os.IsNotExist(err) {
+			log.Print(err)
+		}
+		return
+	}
+	var buf bytes.Buffer
+	c := &runCodec{enc: gob.NewEncoder(&buf)}
+	g.codeRun(c)
+	if c.err != nil {
+		log.Print(c.err)
+		return
+	}
+	name := runFile()
+	if err := os.MkdirAll(filepath.Dir(name), 0700); err != nil {
+		log.Print(err)
+		return
+	}
+	if err := ioutil.WriteFile(name, buf.Bytes(), 0600); err != nil {
+		log.Print(err)
+	}
+}
+
+// resumeRun restores the run saved by Save, if there is one,
+// and reports whether it did. A run can only be resumed once.
+func (g *Game) resumeRun() bool {
+	b, err := ioutil.ReadFile(runFile())
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Print(err)
+		}
+		return false
+	}
+	if err := os.Remove(runFile()); err != nil {
+		log.Print(err)
+	}
+	// Read the run into a copy of the game, so that a bad run
+	// leaves the game as it was. The copy's rng is the game's,
+	// which draws from the game's src, once the copy is in place.
+	r := *g
+	c := &runCodec{dec: gob.NewDecoder(bytes.NewReader(b))}
+	r.codeRun(c)
+	if c.err == nil {
+		c.err = r.checkRun()
+	}
+	if c.err != nil {
+		log.Printf("resuming run: %v", c.err)
+		return false
+	}
+	*g = r
+	g.Leaves = g.Leaves[:0]
+	// The start of the run wasn't recorded.
+	g.recording.ok = false
+	return true
+}
+
+// checkRun returns an error if the run read back into g holds a state,
+// mode, or character the game doesn't have, or anything that is used as
+// an index, such as a ground tile's biome or an entity's texture, out of
+// range, as a corrupt run might.
+func (g *Game) checkRun() error {
+	switch {
+	case g.state != StatePlaying && g.state != StatePaused:
+		return fmt.Errorf("bad state %v", g.state)
+	case g.Mode < 0 || g.Mode >= numModes:
+		return fmt.Errorf("bad mode %d", g.Mode)
+	case g.Character < 0 || g.Character >= len(Characters):
+		return fmt.Errorf("bad character %d", g.Character)
+	case g.Tiles < 1 || g.Tiles > WorldTiles:
+		return fmt.Errorf("bad tile count %d", g.Tiles)
+	case len(g.Entities) > MaxEntities:
+		return fmt.Errorf("%d entities", len(g.Entities))
+	case len(g.Zones) > MaxZones:
+		return fmt.Errorf("%d zones", len(g.Zones))
+	case len(g.splits) > len(SplitMarkers):
+		return fmt.Errorf("%d splits", len(g.splits))
+	}
+	for i := 0; i < g.Tiles; i++ {
+		switch {
+		case g.GroundTex[i] < TexGround1 || g.GroundTex[i] > TexGround4:
+			return fmt.Errorf("tile %d: bad texture %d", i, g.GroundTex[i])
+		case g.GroundType[i] < tileNormal || g.GroundType[i] > TileIce:
+			return fmt.Errorf("tile %d: bad type %d", i, g.GroundType[i])
+		case g.GroundBiome[i] < 0 || g.GroundBiome[i] >= NumBiomes:
+			return fmt.Errorf("tile %d: bad biome %d", i, g.GroundBiome[i])
+		case g.Scenery[i] < SceneryNone || g.Scenery[i] >= len(Sceneries):
+			return fmt.Errorf("tile %d: bad scenery %d", i, g.Scenery[i])
+		}
+	}
+	for i, e := range g.Entities {
+		switch {
+		case e.Kind < EntityCoin || e.Kind > EntityParticle:
+			return fmt.Errorf("entity %d: bad kind %d", i, e.Kind)
+		case e.Tex < 0 || e.Tex >= TexCount:
+			return fmt.Errorf("entity %d: bad texture %d", i, e.Tex)
+		case e.Layer < LayerPlatforms || e.Layer > LayerParticles:
+			return fmt.Errorf("entity %d: bad layer %d", i, e.Layer)
+		}
+	}
+	return nil
+}
+
+// Now returns the time of the game's clock,
+// from which the next call to Update carries on.
+func (g *Game) Now() clock.Time {
+	return g.LastCalc
+}
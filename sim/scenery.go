@@ -0,0 +1,59 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sim
+
+const sceneryProb = 3 // 1/probability of a plain ground tile having scenery
+
+// Kinds of scenery, which stand on the ground
+// and which the gopher runs past without touching.
+const (
+	SceneryNone = iota
+	sceneryTree
+	sceneryPebbles
+	sceneryFlowers
+	sceneryFence
+)
+
+// A Scenery is how a kind of scenery is drawn.
+type Scenery struct {
+	Tex  int
+	W, H float32 // size
+}
+
+var Sceneries = [...]Scenery{
+	sceneryTree:    {TexTree, TileWidth * 2, TileHeight * 2},
+	sceneryPebbles: {TexPebbles, TileWidth, TileHeight / 2},
+	sceneryFlowers: {TexFlowers, TileWidth, TileHeight / 2},
+	sceneryFence:   {TexFence, TileWidth, TileHeight * 3 / 4},
+}
+
+// nextScenery returns the scenery for ground tile i, newly made.
+// The scenery is chosen by where the tile is in the run, rather than
+// with the world's source of randomness, so that it doesn't change the
+// world the gopher runs through.
+func (g *Game) nextScenery(i int) int {
+	if g.GroundType[i] != tileNormal || g.isPit(i) || g.groundSlope[i] {
+		return SceneryNone
+	}
+	if o := g.lastEntity(EntityObstacle); o != nil && o.X == float32(i*TileWidth) {
+		return SceneryNone
+	}
+	h := sceneryHash(g.Distance() + i)
+	kinds := Biomes[g.GroundBiome[i]].scenery
+	if h%sceneryProb != 0 || len(kinds) == 0 {
+		return SceneryNone
+	}
+	return kinds[h/sceneryProb%uint32(len(kinds))]
+}
+
+// sceneryHash scatters the tile numbers n, so that nearby tiles
+// have unrelated scenery.
+func sceneryHash(n int) uint32 {
+	x := uint32(n) * 0x9e3779b1
+	x ^= x >> 16
+	x *= 0x85ebca6b
+	x ^= x >> 13
+	return x
+}
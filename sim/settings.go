@@ -0,0 +1,268 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sim
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const MaxVolume = 10 // loudest sound volume
+
+// The control schemes.
+const (
+	ControlsZones  = "zones"  // touches low on the screen slide and throw
+	ControlsSimple = "simple" // every touch jumps
+	ControlsSwipe  = "swipe"  // swipe down to slide, forward to dash, and back to throw
+	ControlsSplit  = "split"  // touches on the left jump, and on the right throw
+	ControlsTilt   = "tilt"   // tilting the device lifts the gopher, and touches jump
+)
+
+// controlSchemes are the control schemes, in the order the settings show them.
+var controlSchemes = []string{ControlsZones, ControlsSimple, ControlsSwipe, ControlsSplit, ControlsTilt}
+
+// Settings are the player's options, kept on the device.
+type Settings struct {
+	Volume         int    // master volume, from 0 to MaxVolume
+	MusicVolume    int    // music volume, from 0 to MaxVolume, before the master volume
+	SfxVolume      int    // sound effects volume, from 0 to MaxVolume, before the master volume
+	Music          bool   // play the soundtrack
+	Vibration      bool   // vibrate on crashes, big landings, and power-ups
+	VibrationLevel int    // how strongly to vibrate, from 1 to maxVibration
+	Controls       string // control scheme; see ControlsZones and friends
+	Colorblind     bool   // avoid telling things apart by red and green alone
+	HighContrast   bool   // make the ground stand out from the sky
+	Difficulty     string // difficulty preset; see Easy and friends
+	Theme          string // id of the theme chosen; see themes
+	TickRate       int    // simulation steps a second; see tickRates
+
+	// Bindings are the inputs for the actions the player has rebound,
+	// by action name; see ActionNames and defaultBindings.
+	Bindings map[string]Binding
+}
+
+var defaultSettings = Settings{
+	Volume:         MaxVolume * 7 / 10,
+	MusicVolume:    MaxVolume,
+	SfxVolume:      MaxVolume,
+	Music:          true,
+	Vibration:      true,
+	VibrationLevel: 2,
+	Controls:       ControlsZones,
+	Difficulty:     Normal,
+	Theme:          "classic",
+	TickRate:       ClockRate,
+}
+
+func settingsFile() string {
+	return filepath.Join(DataDir(), "settings.json")
+}
+
+// loadSettings reads the saved settings,
+// using the defaults for any that are missing.
+func loadSettings() Settings {
+	s := defaultSettings
+	b, err := ioutil.ReadFile(settingsFile())
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Print(err)
+		}
+		return s
+	}
+	if err := json.Unmarshal(b, &s); err != nil {
+		log.Print(err)
+		return defaultSettings
+	}
+	return s
+}
+
+// saveSettings writes s to disk.
+// Failures are logged; losing settings shouldn't stop the game.
+func saveSettings(s Settings) {
+	b, err := json.Marshal(s)
+	if err != nil {
+		log.Print(err)
+		return
+	}
+	name := settingsFile()
+	if err := os.MkdirAll(filepath.Dir(name), 0700); err != nil {
+		log.Print(err)
+		return
+	}
+	if err := ioutil.WriteFile(name, b, 0600); err != nil {
+		log.Print(err)
+	}
+}
+
+// Settings returns the player's options.
+func (g *Game) Settings() Settings {
+	return g.settings
+}
+
+// SetSettings changes the player's options, saving them
+// and applying them to the game straight away.
+func (g *Game) SetSettings(s Settings) {
+	old := g.Style()
+	g.settings = s
+	saveSettings(s)
+	g.applySettings()
+	g.restyle(old)
+}
+
+// applySettings makes the game follow the player's options.
+func (g *Game) applySettings() {
+	if err := g.SetDifficulty(g.settings.Difficulty); err != nil {
+		log.Print(err)
+	}
+}
+
+// Settings rows.
+const (
+	SettingVolume = iota
+	settingMusicVolume
+	settingSfxVolume
+	settingMusic
+	settingVibration
+	settingVibrationLevel
+	settingControls
+	settingColorblind
+	settingHighContrast
+	settingDifficulty
+	settingTheme
+	settingTickRate
+	settingJumpKey
+	settingPauseKey
+	NumSettings
+)
+
+// KeySettings are the actions whose keys may be rebound, by settings row.
+var KeySettings = map[int]int{
+	settingJumpKey:  ActionJump,
+	settingPauseKey: ActionPause,
+}
+
+// ChangeSetting returns the player's options
+// with setting i stepped forwards or backwards.
+func (g *Game) ChangeSetting(i, d int) Settings {
+	s := g.settings
+	switch i {
+	case SettingVolume:
+		s.Volume = stepVolume(s.Volume, d)
+	case settingMusicVolume:
+		s.MusicVolume = stepVolume(s.MusicVolume, d)
+	case settingSfxVolume:
+		s.SfxVolume = stepVolume(s.SfxVolume, d)
+	case settingMusic:
+		s.Music = !s.Music
+	case settingVibration:
+		s.Vibration = !s.Vibration
+	case settingVibrationLevel:
+		s.VibrationLevel += d
+		if s.VibrationLevel < 1 {
+			s.VibrationLevel = 1
+		}
+		if s.VibrationLevel > maxVibration {
+			s.VibrationLevel = maxVibration
+		}
+	case settingControls:
+		s.Controls = cycleString(controlSchemes, s.Controls, d)
+	case settingColorblind:
+		s.Colorblind = !s.Colorblind
+	case settingHighContrast:
+		s.HighContrast = !s.HighContrast
+	case settingDifficulty:
+		s.Difficulty = cycleString(difficultyNames, s.Difficulty, d)
+	case settingTheme:
+		s.Theme = cycleString(g.unlockedThemes(), s.Theme, d)
+	case settingTickRate:
+		s.TickRate = cycleInt(tickRates, s.TickRate, d)
+	}
+	return s
+}
+
+// stepVolume returns the volume v stepped by d, within 0 to MaxVolume.
+func stepVolume(v, d int) int {
+	v += d
+	if v < 0 {
+		return 0
+	}
+	if v > MaxVolume {
+		return MaxVolume
+	}
+	return v
+}
+
+// cycleString returns the element d places from v in list, wrapping around.
+func cycleString(list []string, v string, d int) string {
+	i := 0
+	for j, w := range list {
+		if w == v {
+			i = j
+		}
+	}
+	n := len(list)
+	return list[((i+d)%n+n)%n]
+}
+
+// cycleInt returns the element d places from v in list, wrapping around.
+func cycleInt(list []int, v, d int) int {
+	i := 0
+	for j, w := range list {
+		if w == v {
+			i = j
+		}
+	}
+	n := len(list)
+	return list[((i+d)%n+n)%n]
+}
+
+// SettingText returns the name and value of setting i of s.
+func SettingText(s Settings, i int) string {
+	onOff := func(b bool) string {
+		if b {
+			return "ON"
+		}
+		return "OFF"
+	}
+	switch i {
+	case SettingVolume:
+		return "VOLUME " + strconv.Itoa(s.Volume)
+	case settingMusicVolume:
+		return "MUSIC VOLUME " + strconv.Itoa(s.MusicVolume)
+	case settingSfxVolume:
+		return "SOUND VOLUME " + strconv.Itoa(s.SfxVolume)
+	case settingMusic:
+		return "MUSIC " + onOff(s.Music)
+	case settingVibration:
+		return "VIBRATION " + onOff(s.Vibration)
+	case settingVibrationLevel:
+		return "VIBRATION LEVEL " + strconv.Itoa(s.VibrationLevel)
+	case settingControls:
+		return "CONTROLS " + strings.ToUpper(s.Controls)
+	case settingColorblind:
+		return "COLORBLIND " + onOff(s.Colorblind)
+	case settingHighContrast:
+		return "HIGH CONTRAST " + onOff(s.HighContrast)
+	case settingDifficulty:
+		return "DIFFICULTY " + strings.ToUpper(s.Difficulty)
+	case settingTheme:
+		return "THEME " + strings.ToUpper(findTheme(s.Theme).name)
+	case settingTickRate:
+		return "PHYSICS " + strconv.Itoa(s.TickRate) + "HZ"
+	case settingJumpKey, settingPauseKey:
+		a := KeySettings[i]
+		k := "NONE"
+		if keys := s.Binding(a).Keys; len(keys) > 0 {
+			k = strings.ToUpper(keys[0])
+		}
+		return strings.ToUpper(ActionNames[a]) + " KEY " + k
+	}
+	return ""
+}
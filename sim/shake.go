@@ -0,0 +1,58 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sim
+
+import (
+	"math"
+
+	"golang.org/x/mobile/exp/sprite/clock"
+)
+
+const (
+	shakeTime    = 30            // how long the camera shakes for
+	crashShake   = TileWidth / 2 // amplitude of the shake when the gopher crashes
+	landingShake = TileWidth / 4 // amplitude of the shake after a big fall
+	hardLanding  = 1.2           // landing velocity that counts as a big fall, as a multiple of the jump velocity
+	shakeFreq    = math.Pi / 2.5 // how quickly the camera shakes, in radians per frame
+)
+
+// A shake is a decaying wobble of the camera.
+type shake struct {
+	start clock.Time // when the shake started
+	amp   float32    // initial amplitude
+}
+
+// shakeCamera starts the camera shaking, unless it is already shaking harder.
+func (g *Game) shakeCamera(amp float32) {
+	if amp < g.shake.amp*g.shakeDecay(g.LastCalc) {
+		return
+	}
+	g.shake = shake{start: g.LastCalc, amp: amp}
+}
+
+// shakeDecay returns the fraction of the shake's amplitude remaining at t.
+func (g *Game) shakeDecay(t clock.Time) float32 {
+	dt := t - g.shake.start
+	if dt < 0 || dt >= shakeTime {
+		return 0
+	}
+	f := 1 - float32(dt)/shakeTime
+	return f * f
+}
+
+// ShakeOffset returns the camera offset at t.
+func (g *Game) ShakeOffset(t clock.Time) (dx, dy float32) {
+	a := g.shake.amp * g.shakeDecay(t)
+	if a == 0 {
+		return 0, 0
+	}
+	phase := float64(t-g.shake.start) * shakeFreq
+	return a * float32(math.Sin(phase)), a * float32(math.Cos(phase*1.3))
+}
+
+// hardLandingV returns the landing velocity that counts as a big fall.
+func (g *Game) hardLandingV() float32 {
+	return -g.physics.JumpV * hardLanding
+}
@@ -0,0 +1,260 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sim
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/mobile/exp/sprite/clock"
+)
+
+const (
+	continuePrice = 40   // coins a continue costs in the shop
+	maxContinues  = 9    // most continues the player may hold
+	upgradePrice  = 150  // coins the first upgrade of a power-up costs; later ones cost more
+	maxUpgrade    = 3    // most times a power-up may be upgraded
+	upgradeBonus  = 0.25 // fraction by which each upgrade extends a power-up
+)
+
+var (
+	errNotEnoughCoins = errors.New("not enough coins")
+	errSoldOut        = errors.New("sold out")
+	errNoRefund       = errors.New("nothing to refund")
+)
+
+// A ShopItem is something the player may buy with coins.
+type ShopItem struct {
+	ID    string // identifies the item to Buy and Refund
+	Name  string // what the player is told
+	Price int    // coins the next one costs, or 0 if it is sold out
+	Owned int    // how many the player has
+}
+
+// A shopItem describes how an item is bought and refunded.
+type shopItem struct {
+	id, Name string
+	max      int                  // most the player may own
+	cost     func(n int) int      // price of the nth one
+	count    func(g *Game) int    // how many the player owns
+	set      func(g *Game, n int) // change how many the player owns
+}
+
+// shopItems returns everything in the shop:
+// the characters, themes, continues, and power-up upgrades.
+func shopItems() []shopItem {
+	var items []shopItem
+	for i, c := range Characters {
+		if c.cost == 0 {
+			continue
+		}
+		i, c := i, c
+		items = append(items, shopItem{
+			id:   "character:" + c.id,
+			Name: c.Name,
+			max:  1,
+			cost: func(int) int { return c.cost },
+			count: func(g *Game) int {
+				if g.characterUnlocked(i) {
+					return 1
+				}
+				return 0
+			},
+			set: func(g *Game, n int) {
+				if n > 0 {
+					g.saved.Characters = append(g.saved.Characters, c.id)
+					return
+				}
+				ids := g.saved.Characters[:0]
+				for _, id := range g.saved.Characters {
+					if id != c.id {
+						ids = append(ids, id)
+					}
+				}
+				g.saved.Characters = ids
+			},
+		})
+	}
+	for _, t := range themes {
+		if t.cost == 0 {
+			continue
+		}
+		t := t
+		items = append(items, shopItem{
+			id:   "theme:" + t.id,
+			Name: t.name + " Theme",
+			max:  1,
+			cost: func(int) int { return t.cost },
+			count: func(g *Game) int {
+				if g.themeUnlocked(t.id) {
+					return 1
+				}
+				return 0
+			},
+			set: func(g *Game, n int) {
+				old := g.Style()
+				if n > 0 {
+					// A theme is put on as soon as it is bought.
+					g.saved.Themes = append(g.saved.Themes, t.id)
+					g.settings.Theme = t.id
+					saveSettings(g.settings)
+				} else {
+					ids := g.saved.Themes[:0]
+					for _, id := range g.saved.Themes {
+						if id != t.id {
+							ids = append(ids, id)
+						}
+					}
+					g.saved.Themes = ids
+				}
+				g.restyle(old)
+			},
+		})
+	}
+	items = append(items, shopItem{
+		id:    "continue",
+		Name:  "Continue",
+		max:   maxContinues,
+		cost:  func(int) int { return continuePrice },
+		count: func(g *Game) int { return g.saved.Continues },
+		set:   func(g *Game, n int) { g.saved.Continues = n },
+	})
+	for _, p := range powerUps {
+		p := p
+		items = append(items, shopItem{
+			id:    "upgrade:" + p.id(),
+			Name:  "Longer " + p.name(),
+			max:   maxUpgrade,
+			cost:  func(n int) int { return upgradePrice * n },
+			count: func(g *Game) int { return g.saved.Upgrades[p.id()] },
+			set: func(g *Game, n int) {
+				if g.saved.Upgrades == nil {
+					g.saved.Upgrades = make(map[string]int)
+				}
+				g.saved.Upgrades[p.id()] = n
+			},
+		})
+	}
+	return items
+}
+
+func findShopItem(id string) (shopItem, error) {
+	for _, it := range shopItems() {
+		if it.id == id {
+			return it, nil
+		}
+	}
+	return shopItem{}, fmt.Errorf("unknown item %q", id)
+}
+
+// price returns the price of the next one of it, or 0 if it is sold out.
+func (it *shopItem) price(g *Game) int {
+	n := it.count(g)
+	if n >= it.max {
+		return 0
+	}
+	return it.cost(n + 1)
+}
+
+// ShopItems returns everything the player may buy.
+func (g *Game) ShopItems() []ShopItem {
+	var items []ShopItem
+	for _, it := range shopItems() {
+		items = append(items, ShopItem{it.id, it.Name, it.price(g), it.count(g)})
+	}
+	return items
+}
+
+// Buy spends coins on the item with the given id.
+func (g *Game) Buy(id string) error {
+	it, err := findShopItem(id)
+	if err != nil {
+		return err
+	}
+	p := it.price(g)
+	if p == 0 {
+		return errSoldOut
+	}
+	if g.saved.Coins < p {
+		return errNotEnoughCoins
+	}
+	g.saved.Coins -= p
+	it.set(g, it.count(g)+1)
+	g.saveProgress()
+	return nil
+}
+
+// Refund returns the last of the item with the given id
+// for the coins it cost.
+func (g *Game) Refund(id string) error {
+	it, err := findShopItem(id)
+	if err != nil {
+		return err
+	}
+	n := it.count(g)
+	if n == 0 {
+		return errNoRefund
+	}
+	g.saved.Coins += it.cost(n)
+	it.set(g, n-1)
+	g.saveProgress()
+	return nil
+}
+
+// powerUpDuration returns how long p lasts, with any upgrades.
+func (g *Game) powerUpDuration(p PowerUp) clock.Time {
+	bonus := 1 + upgradeBonus*float32(g.saved.Upgrades[p.id()])
+	return clock.Time(float32(p.Duration()) * bonus)
+}
+
+// Shopping reports whether the player is in the shop.
+func (g *Game) Shopping() bool {
+	return g.state == StateShop
+}
+
+// OpenShop takes the player from the character select screen to the shop.
+func (g *Game) OpenShop() {
+	if g.state == StateMenu {
+		g.setState(StateShop)
+	}
+}
+
+// CloseShop takes the player back to the character select screen.
+func (g *Game) CloseShop() {
+	g.setState(StateMenu)
+}
+
+// buySelected buys the item being shown in the shop.
+func (g *Game) buySelected() {
+	g.shopMsg = ""
+	if err := g.Buy(shopItems()[g.ShopItem].id); err != nil {
+		g.shopMsg = err.Error()
+	}
+}
+
+// RefundSelected refunds the item being shown in the shop.
+func (g *Game) RefundSelected() {
+	if g.state != StateShop {
+		return
+	}
+	g.shopMsg = ""
+	if err := g.Refund(shopItems()[g.ShopItem].id); err != nil {
+		g.shopMsg = err.Error()
+	}
+}
+
+// ShopPrompt returns what the shop tells the player
+// about the item being shown.
+func (g *Game) ShopPrompt() string {
+	if g.shopMsg != "" {
+		return strings.ToUpper(g.shopMsg)
+	}
+	it := shopItems()[g.ShopItem]
+	if p := it.price(g); p > 0 {
+		return fmt.Sprintf("BUY %d COINS", p)
+	}
+	return "SOLD OUT"
+}
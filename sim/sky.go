@@ -0,0 +1,19 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sim
+
+import "golang.org/x/mobile/exp/sprite/clock"
+
+const dayLength = ClockRate * 120 // length of a full day and night
+
+// calcSky advances the time of day.
+func (g *Game) calcSky(now clock.Time) {
+	g.Day = float32(now%dayLength) / dayLength
+}
+
+// IsNight reports whether it is dark.
+func (g *Game) IsNight() bool {
+	return g.Day > 0.475 && g.Day < 0.925
+}
@@ -0,0 +1,64 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sim
+
+import "math"
+
+const (
+	slopeProb  = 2              // 1/probability of a change in height being a slope rather than a step
+	slopeStart = TilesX         // distance the gopher runs before slopes appear
+	maxSlope   = TileHeight * 2 // steepest slope, as the change in height across a tile
+	slopeSnap  = TileHeight / 2 // how far a gopher running down a slope sticks to it
+)
+
+// nextSlope reports whether the next ground tile, which has
+// y-offset y and type typ, ramps up or down from the last one.
+func (g *Game) nextSlope(y float32, typ int) bool {
+	prev := g.GroundY[g.lastTile()]
+	if y == prev || y == pitY || prev == pitY || typ != tileNormal {
+		return false
+	}
+	if g.Travelled < slopeStart*TileWidth || g.BossActive() {
+		return false
+	}
+	if d := y - prev; d > maxSlope || d < -maxSlope {
+		return false
+	}
+	return g.rng.Intn(slopeProb) == 0
+}
+
+// SlopeY returns the y-offsets of the left and right edges of ground tile i.
+// Tiles on a slope ramp from the height of the tile before them.
+func (g *Game) SlopeY(i int) (y0, y1 float32) {
+	if i > 0 && g.groundSlope[i] {
+		return g.GroundY[i-1], g.GroundY[i]
+	}
+	return g.GroundY[i], g.GroundY[i]
+}
+
+// slopeAt returns the y-offset of ground tile i at x,
+// relative to the ground tiles.
+func (g *Game) slopeAt(i int, x float32) float32 {
+	y0, y1 := g.SlopeY(i)
+	return y0 + (y1-y0)*(x/TileWidth-float32(i))
+}
+
+// groundUnder returns the highest point of the ground between x0 and x1,
+// and the ground tile it is on.
+func (g *Game) groundUnder(x0, x1 float32) (y float32, under int) {
+	y, under = float32(math.MaxFloat32), -1
+	for i := int(x0 / TileWidth); float32(i*TileWidth) < x1; i++ {
+		// The ground is straight across each tile,
+		// so its highest point is at one end.
+		l := float32(math.Max(float64(x0), float64(i*TileWidth)))
+		r := float32(math.Min(float64(x1), float64((i+1)*TileWidth)))
+		for _, x := range []float32{l, r} {
+			if y1 := g.slopeAt(i, x); y1 < y {
+				y, under = y1, i
+			}
+		}
+	}
+	return y, under
+}
@@ -0,0 +1,103 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sim
+
+const maxQueuedSounds = 16 // sound effects the game holds for the mixer to play
+
+const (
+	musicPitchV   = 0.0005 // how much the music's pitch rises with the scroll velocity
+	musicMaxPitch = 1.12   // highest pitch the music rises to
+)
+
+// Sound effects, by name; see sounds.json.
+const (
+	SfxJump      = "jump"
+	SfxFlap      = "flap"
+	SfxLand      = "land"
+	SfxCoin      = "coin"
+	SfxCrash     = "crash"
+	SfxPowerUp   = "powerup"
+	SfxEnemy     = "enemy"     // an enemy is coming
+	SfxObstacle  = "obstacle"  // an obstacle is coming
+	SfxHit       = "hit"       // an acorn hit something
+	SfxMilestone = "milestone" // the run passed a milestone; see stingerEvery
+	SfxBest      = "best"      // the run beat the best score
+)
+
+// A sound is a sound effect the game asks for.
+type Sound struct {
+	Name string  // see SfxJump and friends
+	Pan  float32 // where it is heard, from -1 on the left to 1 on the right
+}
+
+// Music tracks, by asset name; see openSound.
+const (
+	musicTheme  = "music"  // played on the title screen and during runs
+	musicSomber = "somber" // played once the gopher has died
+)
+
+// sound asks for the sound effect called name to be played, straight ahead.
+func (g *Game) sound(name string) {
+	if len(g.sounds) < maxQueuedSounds {
+		g.sounds = append(g.sounds, Sound{Name: name})
+	}
+}
+
+// soundAt asks for the sound effect called name to be played, panned to
+// where x, relative to the ground tiles, is from the gopher, so that
+// the player hears hazards coming before they are seen.
+func (g *Game) soundAt(name string, x float32) {
+	x0, _, _, _ := g.GopherBounds()
+	var pan float32
+	if g.width > 0 {
+		pan = (x - x0) / (g.width / 2)
+	}
+	if pan < -1 {
+		pan = -1
+	}
+	if pan > 1 {
+		pan = 1
+	}
+	if len(g.sounds) < maxQueuedSounds {
+		g.sounds = append(g.sounds, Sound{name, pan})
+	}
+}
+
+// Sounds returns the sound effects asked for since it was last called.
+func (g *Game) Sounds() []Sound {
+	s := g.sounds
+	g.sounds = nil
+	return s
+}
+
+// Ducked reports whether the music should be quietened:
+// while the game is paused, and while the gopher dies.
+func (g *Game) Ducked() bool {
+	return g.state == StatePaused || g.state == StateDead
+}
+
+// MusicPitch returns the pitch the music plays at, which rises
+// as the ground scrolls faster, so that the music speeds up with the game.
+func (g *Game) MusicPitch() float32 {
+	p := 1 + musicPitchV*(g.Scroll.V-initScrollV)
+	if p < 1 {
+		return 1
+	}
+	if p > musicMaxPitch {
+		return musicMaxPitch
+	}
+	return p
+}
+
+// Music returns the track the game should be playing, or "" for none.
+func (g *Game) Music() string {
+	switch {
+	case !g.settings.Music:
+		return ""
+	case g.Over():
+		return musicSomber
+	}
+	return musicTheme
+}
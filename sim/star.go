@@ -0,0 +1,20 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sim
+
+const (
+	starT     = ClockRate * 8 // duration of the invincibility star
+	starBonus = 25            // points awarded for each obstacle smashed
+	StarCycle = 4             // how long the gopher shows each tint
+)
+
+// GopherSetSize is the number of textures in a tinted set of gopher textures.
+const GopherSetSize = TexGopherGlide - TexGopherRun1 + 1
+
+// smashObstacle awards the points for knocking an obstacle
+// out of the way of a gopher with the star.
+func (g *Game) smashObstacle() {
+	g.points += starBonus * TileWidth
+}
@@ -0,0 +1,80 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sim
+
+import "log"
+
+// A State is what the game is doing, which decides how Update moves it on,
+// what the player's input does, and what is drawn.
+type State int
+
+// Game states.
+const (
+	StateMenu    State = iota // the player is choosing a character
+	StateShop                 // the player is in the shop
+	StatePlaying              // the gopher is running
+	StatePaused               // the run is held still
+	StateDead                 // the run is over, and the gopher falls off screen or runs on
+	StateResults              // the summary of the run just ended is shown
+	numStates
+)
+
+var stateNames = [numStates]string{"menu", "shop", "playing", "paused", "dead", "results"}
+
+func (s State) String() string {
+	if s < 0 || s >= numStates {
+		return "unknown"
+	}
+	return stateNames[s]
+}
+
+// transitions are the states the game may go to from each state.
+var transitions = [numStates][]State{
+	StateMenu:    {StateShop, StatePlaying},
+	StateShop:    {StateMenu},
+	StatePlaying: {StatePaused, StateDead, StateMenu},
+	StatePaused:  {StatePlaying},
+	StateDead:    {StateResults, StatePlaying},
+	StateResults: {StatePlaying, StateMenu},
+}
+
+// State returns what the game is doing.
+func (g *Game) State() State {
+	return g.state
+}
+
+// setState moves the game on to state s, and gets s ready to be shown.
+// Moves that the game doesn't make, such as from the shop straight
+// into a run, are logged and ignored.
+func (g *Game) setState(s State) {
+	if s == g.state {
+		return
+	}
+	ok := false
+	for _, t := range transitions[g.state] {
+		ok = ok || t == s
+	}
+	if !ok {
+		log.Printf("game can't go from %v to %v", g.state, s)
+		return
+	}
+	g.state = s
+	switch s {
+	case StateShop:
+		g.shopMsg = ""
+	case StatePaused:
+		g.pauseItem = pauseResume
+		g.letGo()
+	case StateResults:
+		g.summaryItem = summaryRetry
+		g.replaying = false
+	}
+}
+
+// Over reports whether the run is over: the gopher has died,
+// or crossed the finish line, and the run's results are due or shown.
+func (g *Game) Over() bool {
+	return g.state == StateDead || g.state == StateResults
+}
@@ -0,0 +1,39 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sim
+
+import "time"
+
+// Stats are the player's lifetime statistics.
+type Stats struct {
+	Distance int           // tiles run
+	Jumps    int           // jumps off the ground
+	Flaps    int           // flaps in mid-air
+	Coins    int           // coins collected
+	Deaths   int           // runs ended
+	Climbs   int           // cliffs climbed
+	PlayTime time.Duration // time spent running
+}
+
+// Stats returns the player's lifetime statistics.
+func (g *Game) Stats() Stats {
+	t := g.saved.Totals
+	return Stats{
+		Distance: t[eventMetre],
+		Jumps:    t[eventJump],
+		Flaps:    t[eventFlap],
+		Coins:    t[eventCoin],
+		Deaths:   t[eventDeath],
+		Climbs:   t[eventClimb],
+		PlayTime: time.Duration(g.saved.PlayTime) * time.Second / ClockRate,
+	}
+}
+
+// calcPlayTime counts the ticks the gopher spends running.
+func (g *Game) calcPlayTime() {
+	if !g.Over() && !g.replaying {
+		g.saved.PlayTime++
+	}
+}
@@ -0,0 +1,50 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sim
+
+const (
+	stingerEvery    = 500 // distance between milestone stingers
+	stingerCooldown = 180 // how long after a stinger before another may play
+)
+
+// stinger plays the jingle called name, unless another has only just played,
+// so that stingers close together, such as a milestone reached as the best
+// score is beaten, don't pile up.
+func (g *Game) stinger(name string) {
+	if g.LastCalc < g.stingUntil {
+		return
+	}
+	g.stingUntil = g.LastCalc + stingerCooldown
+	g.sound(name)
+}
+
+// calcStingers celebrates the run passing a milestone, if it just did,
+// and beating the player's best score.
+func (g *Game) calcStingers(milestone bool) {
+	if milestone {
+		g.stinger(SfxMilestone)
+	}
+	if g.beatBest {
+		return
+	}
+	if best, ok := g.bestScore(); ok && best > 0 && g.Score() > best {
+		g.beatBest = true
+		g.stinger(SfxBest)
+	}
+}
+
+// bestScore returns the best distance score of the mode,
+// and whether the mode keeps one.
+func (g *Game) bestScore() (int, bool) {
+	switch {
+	case g.Mode == Sprint || g.Mode == TimeAttack || g.Mode == Zen:
+		return 0, false
+	case g.Mode == Survival:
+		return g.saved.SurvivalBest, true
+	case g.daily:
+		return g.dailyBest(), true
+	}
+	return g.saved.Best, true
+}
@@ -0,0 +1,201 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sim
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"golang.org/x/mobile/exp/sprite/clock"
+)
+
+// progress is the player's progress, persisted across app launches.
+type progress struct {
+	Best  int // best score
+	Coins int // coins collected over all runs
+
+	Missions    []mission // the player's current missions
+	NextMission int       // the next of missionTemplates to set
+
+	Totals   map[gameEvent]int // lifetime count of each event
+	PlayTime clock.Time        // frames spent running
+	Unlocked []string          // ids of the achievements unlocked
+
+	Character  string   // id of the character last chosen
+	Characters []string // ids of the characters unlocked
+
+	Themes []string // ids of the themes unlocked
+
+	Continues int            // continues bought in the shop
+	Upgrades  map[string]int // level of each power-up's upgrade, by power-up id
+
+	XP int // experience earned over all runs
+
+	DailyBest int    // best score in the daily challenge
+	DailyDate string // day of DailyBest, as YYYY-MM-DD
+
+	SprintBest   clock.Time   // fastest sprint, or 0 if none has been finished
+	SurvivalBest int          // best score in survival mode
+	BestSplits   []clock.Time // split times of the fastest time attack
+
+	Modified map[string]int64 // when each field last changed, in Unix nanoseconds, for syncing
+}
+
+// Coins returns the coins the player has to spend in the shop.
+func (g *Game) Coins() int {
+	return g.saved.Coins
+}
+
+// Continues returns the continues the player has bought in the shop.
+func (g *Game) Continues() int {
+	return g.saved.Continues
+}
+
+// DataDir returns the directory in which the game keeps its files,
+// which last until the game is uninstalled.
+func DataDir() string {
+	if dir, err := os.UserConfigDir(); err == nil {
+		return filepath.Join(dir, "flappy")
+	}
+	// Android apps have no config directory. Their temporary directory
+	// is their cache, which the system clears when it runs short of
+	// space, but beside it is their private files directory, which it
+	// keeps.
+	return filepath.Join(filepath.Dir(os.TempDir()), "files")
+}
+
+func progressFile() string {
+	return filepath.Join(DataDir(), "progress.json")
+}
+
+// loadProgress reads the saved progress.
+// If there is none, it returns the zero progress.
+func loadProgress() progress {
+	var p progress
+	b, err := ioutil.ReadFile(progressFile())
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Print(err)
+		}
+		return p
+	}
+	if err := json.Unmarshal(b, &p); err != nil {
+		log.Print(err)
+	}
+	return p
+}
+
+// progressMu guards the progress file against being saved
+// while it is being synced.
+var progressMu sync.Mutex
+
+// saveProgress saves the player's progress.
+func (g *Game) saveProgress() {
+	g.saved, g.savedJSON = writeProgress(g.saved, g.savedJSON)
+}
+
+// writeProgress writes the fields of p that differ from base, the JSON of
+// the progress as the game last loaded or saved it, noting when they changed.
+// The other fields are left as they are on disk, where a sync may have merged
+// in newer copies of them from another device. It returns the progress as
+// written, and its JSON, the next base.
+// Failures are logged; losing progress shouldn't stop the game.
+func writeProgress(p progress, base []byte) (progress, []byte) {
+	progressMu.Lock()
+	defer progressMu.Unlock()
+
+	disk, err := readProgressFields()
+	if err != nil {
+		log.Print(err)
+	}
+	var old, fields map[string]json.RawMessage
+	if len(base) > 0 {
+		if err := json.Unmarshal(base, &old); err != nil {
+			log.Print(err)
+		}
+	}
+	if err := json.Unmarshal(progressJSON(p), &fields); err != nil {
+		log.Print(err)
+		return p, base
+	}
+	mod := modifiedTimes(disk)
+	now := time.Now().UnixNano()
+	for k, v := range fields {
+		if k == "Modified" {
+			continue
+		}
+		if !bytes.Equal(old[k], v) {
+			mod[k] = now
+		} else if d, ok := disk[k]; ok {
+			fields[k] = d
+		}
+	}
+	b, err := json.Marshal(mod)
+	if err != nil {
+		log.Print(err)
+		return p, base
+	}
+	fields["Modified"] = b
+	if b, err = json.Marshal(fields); err != nil {
+		log.Print(err)
+		return p, base
+	}
+	if err := writeProgressFile(b); err != nil {
+		log.Print(err)
+	}
+	var q progress
+	if err := json.Unmarshal(b, &q); err != nil {
+		log.Print(err)
+		return p, base
+	}
+	return q, progressJSON(q)
+}
+
+// progressJSON returns the JSON of p, apart from when its fields changed.
+func progressJSON(p progress) []byte {
+	p.Modified = nil
+	b, err := json.Marshal(p)
+	if err != nil {
+		log.Print(err)
+		return nil
+	}
+	return b
+}
+
+// readProgressFields reads the saved progress, field by field.
+func readProgressFields() (map[string]json.RawMessage, error) {
+	b, err := ioutil.ReadFile(progressFile())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+func writeProgressFile(b []byte) error {
+	name := progressFile()
+	if err := os.MkdirAll(filepath.Dir(name), 0700); err != nil {
+		return err
+	}
+	// Write to a temporary file first so that a crash
+	// can't leave a truncated progress file behind.
+	tmp := name + ".tmp"
+	if err := ioutil.WriteFile(tmp, b, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, name)
+}
@@ -0,0 +1,44 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sim
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+)
+
+// TestSaveAfterSync checks that saving the progress keeps the fields
+// a sync merged in, rather than the game's stale copies of them.
+func TestSaveAfterSync(t *testing.T) {
+	os.Remove(progressFile())
+	p := loadProgress()
+	base := progressJSON(p)
+	p.Coins = 10
+	p, base = writeProgress(p, base)
+
+	// Another device set a new best, and a sync merged it in.
+	fields, err := readProgressFields()
+	if err != nil {
+		t.Fatal(err)
+	}
+	fields["Best"] = json.RawMessage("99")
+	b, err := json.Marshal(fields)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := writeProgressFile(b); err != nil {
+		t.Fatal(err)
+	}
+
+	p.XP = 5
+	p, _ = writeProgress(p, base)
+	if p.Best != 99 || p.Coins != 10 || p.XP != 5 {
+		t.Errorf("progress saved as best %d, coins %d, XP %d; want 99, 10, 5", p.Best, p.Coins, p.XP)
+	}
+	if got := loadProgress(); got.Best != 99 || got.Coins != 10 || got.XP != 5 {
+		t.Errorf("progress loaded as best %d, coins %d, XP %d; want 99, 10, 5", got.Best, got.Coins, got.XP)
+	}
+}
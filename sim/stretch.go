@@ -0,0 +1,35 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sim
+
+import "golang.org/x/mobile/exp/sprite/clock"
+
+const (
+	StretchTime = 12   // frames a squash or stretch lasts
+	jumpStretch = 0.3  // how much taller the gopher is stretched when it jumps
+	flapStretch = 0.15 // how much taller the gopher is stretched when it flaps
+	landSquash  = 0.3  // how much shorter the gopher is squashed by a hard landing
+)
+
+// A Stretch briefly squashes or stretches the gopher,
+// to exaggerate its jumps and landings.
+type Stretch struct {
+	Start  clock.Time // when the stretch started
+	Amount float32    // how much taller the gopher is made, or shorter if negative
+}
+
+// stretchGopher starts the gopher stretching, or squashing if amount is negative.
+func (g *Game) stretchGopher(amount float32) {
+	g.Stretch = Stretch{Start: g.LastCalc, Amount: amount}
+}
+
+// landingSquash returns how much a gopher landing with velocity v,
+// as a fraction of the velocity of a hard landing, is squashed.
+func landingSquash(f float32) float32 {
+	if f > 1 {
+		f = 1
+	}
+	return -landSquash * f
+}
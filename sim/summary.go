@@ -0,0 +1,98 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sim
+
+import "strconv"
+
+// Game over summary options.
+const (
+	summaryRetry = iota
+	summaryMenu
+	summaryShare
+	summaryReplay
+	numSummaryOptions
+)
+
+var summaryOptions = [numSummaryOptions]string{"RETRY", "MENU", "SHARE CLIP", "WATCH REPLAY"}
+
+// A Clips records the end of each run as a clip the player may share.
+type Clips interface {
+	Reset()         // forget the clip of the last run, as a new one starts
+	Save()          // share the clip of the run just ended
+	Status() string // what became of the clip last shared
+}
+
+// noClips is the Clips of a game that doesn't record its runs.
+type noClips struct{}
+
+func (noClips) Reset()         {}
+func (noClips) Save()          {}
+func (noClips) Status() string { return "NO CLIPS RECORDED" }
+
+// GameOver reports whether the summary of the run just ended is shown.
+func (g *Game) GameOver() bool {
+	return g.state == StateResults
+}
+
+// chooseSummaryOption does what the selected summary option says.
+func (g *Game) chooseSummaryOption() {
+	switch g.summaryItem {
+	case summaryRetry:
+		g.transitionTo(g.reset)
+	case summaryMenu:
+		g.transitionTo(func() {
+			g.reset()
+			g.setState(StateMenu)
+		})
+	case summaryShare:
+		g.clips.Save()
+	case summaryReplay:
+		if g.recording.ok {
+			g.transitionTo(g.startReplay)
+		}
+	}
+}
+
+// SummaryLines returns the results of the run just ended.
+func (g *Game) SummaryLines() []string {
+	if !g.GameOver() {
+		return nil
+	}
+	return []string{
+		"DISTANCE " + strconv.Itoa(g.Distance()),
+		"COINS " + strconv.Itoa(g.Collected),
+		g.BestText(),
+	}
+}
+
+// SummaryTitle returns the heading of the summary.
+func (g *Game) SummaryTitle() string {
+	switch {
+	case !g.GameOver():
+		return ""
+	case g.Finished():
+		return "FINISHED"
+	}
+	return "GAME OVER"
+}
+
+// SummaryPrompt returns the summary option being shown.
+func (g *Game) SummaryPrompt() string {
+	if !g.GameOver() {
+		return ""
+	}
+	if g.summaryItem == summaryReplay && !g.recording.ok {
+		return "< NO REPLAY >"
+	}
+	return "< " + summaryOptions[g.summaryItem] + " >"
+}
+
+// ClipText returns what became of the clip the player last shared.
+func (g *Game) ClipText() string {
+	if !g.GameOver() {
+		return ""
+	}
+	return g.clips.Status()
+}
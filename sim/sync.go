@@ -0,0 +1,135 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sim
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+	"time"
+)
+
+// syncURLEnv names the environment variable holding the URL
+// of the player's progress on the sync server.
+// The server need only store the JSON document that is PUT to the URL
+// and return it from GET, or 404 if there is none yet.
+const syncURLEnv = "FLAPPY_SYNC_URL"
+
+var syncClient = &http.Client{Timeout: 10 * time.Second}
+
+// SyncInBackground syncs the saved progress with the server,
+// if there is one, without holding up the game.
+// The game picks up any changes at the start of the next run, and
+// saving its progress before then only overwrites the fields it changed.
+func SyncInBackground() {
+	url := os.Getenv(syncURLEnv)
+	if url == "" {
+		return
+	}
+	go func() {
+		if err := syncProgress(url); err != nil {
+			log.Printf("sync: %v", err)
+		}
+	}()
+}
+
+// syncProgress pulls the progress at url, merges it with the
+// progress saved on this device, and pushes the result back.
+func syncProgress(url string) error {
+	remote, err := pullProgress(url)
+	if err != nil {
+		return err
+	}
+
+	progressMu.Lock()
+	defer progressMu.Unlock()
+	local, err := readProgressFields()
+	if err != nil {
+		return err
+	}
+	merged := mergeProgress(local, remote)
+	b, err := json.Marshal(merged)
+	if err != nil {
+		return err
+	}
+	if err := writeProgressFile(b); err != nil {
+		return err
+	}
+	return pushProgress(url, b)
+}
+
+func pullProgress(url string) (map[string]json.RawMessage, error) {
+	resp, err := syncClient.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		// Nothing has been synced yet.
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s: %s", url, resp.Status)
+	}
+	var fields map[string]json.RawMessage
+	if err := json.NewDecoder(resp.Body).Decode(&fields); err != nil {
+		return nil, fmt.Errorf("GET %s: %v", url, err)
+	}
+	return fields, nil
+}
+
+func pushProgress(url string, b []byte) error {
+	req, err := http.NewRequest("PUT", url, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := syncClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	ioutil.ReadAll(resp.Body)
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("PUT %s: %s", url, resp.Status)
+	}
+	return nil
+}
+
+// mergeProgress merges two copies of the progress, field by field,
+// taking whichever copy of each field was changed last.
+func mergeProgress(local, remote map[string]json.RawMessage) map[string]json.RawMessage {
+	lm, rm := modifiedTimes(local), modifiedTimes(remote)
+	merged := make(map[string]json.RawMessage)
+	for k, v := range local {
+		merged[k] = v
+	}
+	for k, v := range remote {
+		if _, ok := local[k]; !ok || rm[k] > lm[k] {
+			merged[k] = v
+			lm[k] = rm[k]
+		}
+	}
+	delete(merged, "Modified")
+	if b, err := json.Marshal(lm); err == nil {
+		merged["Modified"] = b
+	}
+	return merged
+}
+
+// modifiedTimes returns when each field of the progress was last changed.
+func modifiedTimes(fields map[string]json.RawMessage) map[string]int64 {
+	m := make(map[string]int64)
+	if b, ok := fields["Modified"]; ok {
+		if err := json.Unmarshal(b, &m); err != nil {
+			log.Printf("sync: %v", err)
+		}
+	}
+	return m
+}
@@ -0,0 +1,94 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sim
+
+// A Theme recolours the ground, earth, and sky.
+type Theme struct {
+	id, name string
+	cost     int // coins it costs to unlock, or 0 if it is free
+
+	// Tint recolours the textures, on top of each biome's tint.
+	// It is given and returns premultiplied red, green, and blue.
+	Tint func(r, g, b float32) (float32, float32, float32)
+}
+
+var themes = []Theme{
+	{
+		id:   "classic",
+		name: "Classic",
+		Tint: func(r, g, b float32) (float32, float32, float32) { return r, g, b },
+	},
+	{
+		id:   "autumn",
+		name: "Autumn",
+		cost: 300,
+		Tint: func(r, g, b float32) (float32, float32, float32) {
+			// Turning leaves under a warm sky.
+			return r*1.1 + g*0.4, g * 0.75, b * 0.5
+		},
+	},
+	{
+		id:   "winter",
+		name: "Winter",
+		cost: 300,
+		Tint: func(r, g, b float32) (float32, float32, float32) {
+			// Snow and frost under a pale sky.
+			l := (r + g + b) / 3
+			return l*1.1 + r*0.1, l * 1.15, l * 1.3
+		},
+	},
+	{
+		id:   "lava",
+		name: "Lava",
+		cost: 600,
+		Tint: func(r, g, b float32) (float32, float32, float32) {
+			// Scorched rock under a burning sky.
+			l := (r + g + b) / 3
+			return l*1.4 + r*0.3, l * 0.35, l * 0.2
+		},
+	},
+}
+
+// findTheme returns the theme with the given id, or the classic theme.
+func findTheme(id string) *Theme {
+	for i := range themes {
+		if themes[i].id == id {
+			return &themes[i]
+		}
+	}
+	return &themes[0]
+}
+
+// themeUnlocked reports whether the player may use the theme with the given id.
+func (g *Game) themeUnlocked(id string) bool {
+	if findTheme(id).cost == 0 {
+		return true
+	}
+	for _, u := range g.saved.Themes {
+		if u == id {
+			return true
+		}
+	}
+	return false
+}
+
+// unlockedThemes returns the ids of the themes the player may use.
+func (g *Game) unlockedThemes() []string {
+	var ids []string
+	for _, t := range themes {
+		if g.themeUnlocked(t.id) {
+			ids = append(ids, t.id)
+		}
+	}
+	return ids
+}
+
+// theme returns the theme the player has chosen, if they may use it.
+func (g *Game) theme() *Theme {
+	if !g.themeUnlocked(g.settings.Theme) {
+		return &themes[0]
+	}
+	return findTheme(g.settings.Theme)
+}
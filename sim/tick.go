@@ -0,0 +1,45 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sim
+
+import "math"
+
+// The game's clock ticks ClockRate times a second, and times such as
+// slideTime are counted in its ticks. The simulation steps a whole number
+// of times each tick, as the player chooses, moving things by their
+// velocities in pixels per second, so the game feels the same whatever
+// the rate.
+
+const ClockRate = 60 // ticks of the clock a second
+
+// tickRates are the simulation rates the player may choose, in steps a second.
+// Each is a multiple of ClockRate.
+var tickRates = []int{ClockRate, ClockRate * 2, ClockRate * 4}
+
+// substeps returns how many times the simulation steps each tick of the clock.
+func (g *Game) substeps() int {
+	if n := g.settings.TickRate / ClockRate; n > 1 {
+		return n
+	}
+	return 1
+}
+
+// dt returns how long each step of the simulation lasts, in seconds.
+func (g *Game) dt() float32 {
+	return 1 / float32(ClockRate*g.substeps())
+}
+
+// ease returns the fraction of the way to its target that something
+// closing in on it at rate, per second, moves in a step of the simulation.
+func (g *Game) ease(rate float32) float32 {
+	return 1 - float32(math.Exp(float64(-rate*g.dt())))
+}
+
+// calcTick does what happens once each tick of the clock,
+// after the simulation has stepped through it.
+func (g *Game) calcTick() {
+	g.calcPlayTime()
+	g.calcMode()
+}
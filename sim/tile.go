@@ -0,0 +1,61 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sim
+
+// Ground tile types.
+const (
+	tileNormal = iota // plain ground
+	TileSpring        // launches the gopher into the air
+	TileSpikes        // kills the gopher
+	TileLava          // kills the gopher
+	TileWater         // the bottom of a pool, which the gopher swims through
+	TileIce           // slippery ground, on which the gopher skids
+)
+
+const (
+	springProb  = 25         // 1/probability of a new tile having a spring
+	springStart = TilesX * 2 // distance the gopher runs before springs appear
+	springJump  = 1.5        // how much higher than a jump a spring launches the gopher
+	hazardProb  = 15         // 1/probability of a new tile being hazardous
+	hazardStart = TilesX * 3 // distance the gopher runs before hazards appear
+)
+
+// nextGroundType returns the type of the next ground tile,
+// which has y-offset y.
+func (g *Game) nextGroundType(y float32) int {
+	if g.poolLeft > 0 {
+		g.poolLeft--
+		return TileWater
+	}
+	if y == pitY || g.Travelled < springStart*TileWidth || g.BossActive() {
+		return tileNormal
+	}
+	if g.rng.Intn(springProb) == 0 {
+		return TileSpring
+	}
+	prev := g.GroundType[g.lastTile()]
+	if g.Travelled >= hazardStart*TileWidth && prev == tileNormal && g.rng.Intn(g.escalate(hazardProb)) == 0 {
+		// Never put hazards side by side, so they can always be hopped over.
+		return TileSpikes + g.rng.Intn(2)
+	}
+	if g.nextIce() {
+		return TileIce
+	}
+	return tileNormal
+}
+
+// landOn is called when the gopher lands on ground tile i.
+// It reports whether the gopher bounced off.
+func (g *Game) landOn(i int) bool {
+	switch g.GroundType[i] {
+	case TileSpring:
+		g.Gopher.V = g.physics.JumpV * springJump
+		return true
+	case TileSpikes, TileLava:
+		g.killGopher(causeHazard)
+		return g.Gopher.V < 0
+	}
+	return false
+}
@@ -0,0 +1,35 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sim
+
+import "time"
+
+// With the tilt controls, tipping the top of the device away from the
+// player lifts the gopher gently, and tipping it back lets it sink.
+const (
+	tiltForce = 0.6                   // upward force on the gopher when tilted fully, as a fraction of gravity
+	TiltRange = 4                     // change in acceleration, in m/s², of a full tilt
+	TiltDelay = 20 * time.Millisecond // how often the accelerometer is read
+)
+
+// SetTilt sets how far the device is tilted, from -1 to 1,
+// to lift the gopher or let it sink.
+func (g *Game) SetTilt(f float32) {
+	if !g.Choosing() && !g.Paused() && !g.takeInput(inputTilt, false, f) {
+		return
+	}
+	switch {
+	case f < -1:
+		f = -1
+	case f > 1:
+		f = 1
+	}
+	g.tilt = f
+}
+
+// tiltV returns the change in the gopher's velocity due to the tilt.
+func (g *Game) tiltV() float32 {
+	return -g.physics.Gravity * tiltForce * g.tilt
+}
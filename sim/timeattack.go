@@ -0,0 +1,64 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sim
+
+import "golang.org/x/mobile/exp/sprite/clock"
+
+const splitShown = ClockRate * 3 // how long a split time is shown for
+
+// SplitMarkers are the distances at which time attack splits are taken.
+// The last is the finish line.
+var SplitMarkers = []int{250, 500, 1000, 1500, 2000}
+
+// calcSplits takes a split as the gopher passes each marker,
+// finishing the run at the last.
+func (g *Game) calcSplits() {
+	n := len(g.splits)
+	if n == len(SplitMarkers) || g.Distance() < SplitMarkers[n] {
+		return
+	}
+	g.splits = append(g.splits, g.runTime)
+	if len(g.splits) == len(SplitMarkers) {
+		g.finishRun()
+	}
+}
+
+// recordSplits keeps the splits of a finished run if it was the fastest.
+func (g *Game) recordSplits() {
+	best := g.saved.BestSplits
+	if !g.Finished() || len(best) == len(SplitMarkers) && best[len(best)-1] <= g.runTime {
+		return
+	}
+	g.saved.BestSplits = append([]clock.Time(nil), g.splits...)
+}
+
+// SplitText returns the difference between the latest split
+// and the same split of the fastest run, while it is shown.
+func (g *Game) SplitText() string {
+	n := len(g.splits)
+	if n == 0 || g.LastCalc-g.splitTime() > splitShown {
+		return ""
+	}
+	if n > len(g.saved.BestSplits) {
+		return formatTime(g.splits[n-1])
+	}
+	d := g.splits[n-1] - g.saved.BestSplits[n-1]
+	if d < 0 {
+		return "-" + formatTime(-d)
+	}
+	return "+" + formatTime(d)
+}
+
+// splitTime returns when the latest split was taken.
+func (g *Game) splitTime() clock.Time {
+	return g.LastCalc - (g.runTime - g.splits[len(g.splits)-1])
+}
+
+// MarkerX returns the x-offset on screen of the split marker at
+// distance d, and whether it is on screen.
+func (g *Game) MarkerX(d int) (float32, bool) {
+	x := float32(d*TileWidth) - g.Travelled + TileWidth*GopherTile
+	return x, x > -TileWidth && x < g.width
+}
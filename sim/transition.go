@@ -0,0 +1,47 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sim
+
+import "golang.org/x/mobile/exp/sprite/clock"
+
+const FadeTime = 20 // how long it takes to fade to black, and back again
+
+// A Transition fades the scene to black, changes it, and fades it back in.
+type Transition struct {
+	Active bool       // is a transition under way?
+	Start  clock.Time // when the scene started fading out
+	then   func()     // changes the scene while it is black, or nil once done
+}
+
+// transitionTo fades the scene out, calls fn, and fades it back in.
+// It does nothing if a transition is already under way.
+func (g *Game) transitionTo(fn func()) {
+	if g.Fade.Active {
+		return
+	}
+	g.Fade = Transition{Active: true, Start: g.LastCalc, then: fn}
+}
+
+// cutTo cuts straight to black, calls fn, and fades the scene back in.
+func (g *Game) cutTo(fn func()) {
+	g.Fade = Transition{Active: true, Start: g.LastCalc - FadeTime, then: fn}
+}
+
+// calcTransition changes the scene once it has faded to black,
+// and ends the transition once it has faded back in.
+func (g *Game) calcTransition(now clock.Time) {
+	if !g.Fade.Active {
+		return
+	}
+	dt := now - g.Fade.Start
+	if dt >= FadeTime && g.Fade.then != nil {
+		fn := g.Fade.then
+		g.Fade.then = nil
+		fn()
+	}
+	if dt >= FadeTime*2 {
+		g.Fade.Active = false
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sim
+
+const (
+	poolProb     = 25             // 1/probability of a new tile starting a pool
+	poolStart    = TilesX * 4     // distance the gopher runs before pools appear
+	minPool      = 3              // minimum width of a pool, in tiles
+	maxPool      = 5              // maximum width of a pool, in tiles
+	PoolDepth    = TileHeight * 2 // depth of the water in a pool
+	waterGravity = 0.3            // gravity in water, as a fraction of the usual
+	sinkV        = 45             // fastest the gopher sinks through water
+	strokeJump   = 0.4            // how much of a jump a stroke through water is
+	drownTime    = 3              // how many seconds the gopher can hold its breath
+	bubbleRate   = 4              // bubbles breathed out per second under water
+)
+
+var bubbleBurst = burst{TexRing, 2, 12, 30, -30, TileWidth / 4, 40}
+
+// nextPoolY returns the ground y-offset of the next tile
+// if it is part of a pool or the ground just beyond one.
+// The tiles of the pool itself are given their type by nextGroundType.
+func (g *Game) nextPoolY() (y float32, ok bool) {
+	last := g.lastTile()
+	prev := g.GroundY[last]
+	switch {
+	case g.poolLeft > 0:
+		return g.poolEdge + PoolDepth, true
+	case g.GroundType[last] == TileWater:
+		// The far side of a pool is as high as the near side.
+		return g.poolEdge, true
+	case g.Travelled >= poolStart*TileWidth && prev != pitY && g.rng.Intn(poolProb) == 0:
+		g.poolEdge = prev
+		g.poolLeft = minPool + g.rng.Intn(maxPool-minPool+1)
+		return prev + PoolDepth, true
+	}
+	return 0, false
+}
+
+// WaterY returns the y-offset of the water's surface in ground tile i.
+func (g *Game) WaterY(i int) float32 {
+	return g.GroundY[i] - PoolDepth
+}
+
+// footTile returns the ground tile the middle of the gopher is over.
+func (g *Game) footTile() int {
+	x0, _, x1, _ := g.GopherBounds()
+	return int((x0 + x1) / 2 / TileWidth)
+}
+
+// InWater reports whether the gopher is in a pool.
+func (g *Game) InWater() bool {
+	i := g.footTile()
+	return g.GroundType[i] == TileWater && g.Gopher.Y+TileHeight > g.WaterY(i)
+}
+
+// submerged reports whether the gopher's head is under water.
+func (g *Game) submerged() bool {
+	i := g.footTile()
+	return g.GroundType[i] == TileWater && g.Gopher.Y > g.WaterY(i)
+}
+
+// stroke makes the gopher swim upward.
+func (g *Game) stroke() {
+	g.Gopher.V = g.jumpV * strokeJump
+	g.stretchGopher(flapStretch)
+	g.sound(SfxFlap)
+	g.emit(bubbleBurst, g.Scroll.X+TileWidth*GopherTile+TileWidth/2, g.Gopher.Y+TileHeight/2)
+}
+
+// calcSwim slows the gopher's sinking through water
+// and drowns it if it stays under for too long.
+func (g *Game) calcSwim() {
+	if !g.InWater() {
+		g.Gopher.breath = 0
+		return
+	}
+	if g.Gopher.V > sinkV {
+		g.Gopher.V = sinkV
+	}
+	g.Gopher.flaps = 0
+	if !g.submerged() {
+		g.Gopher.breath = 0
+		return
+	}
+	was := g.Gopher.breath
+	g.Gopher.breath += g.dt()
+	if int(g.Gopher.breath*bubbleRate) != int(was*bubbleRate) {
+		g.emit(bubbleBurst, g.Scroll.X+TileWidth*GopherTile+TileWidth*3/4, g.Gopher.Y)
+	}
+	if !g.Over() && g.Gopher.breath > drownTime {
+		g.killGopher(causeDrown)
+	}
+}
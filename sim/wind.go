@@ -0,0 +1,126 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sim
+
+import (
+	"math"
+	"math/rand"
+
+	"golang.org/x/mobile/exp/sprite/clock"
+)
+
+const (
+	windStart   = TilesX * 6     // distance the gopher runs before the wind blows
+	windMinGap  = ClockRate * 10 // minimum time between gusts
+	windMaxGap  = ClockRate * 20 // maximum time between gusts
+	windWarning = ClockRate      // how long before a gust arrives it is heralded
+	windTime    = ClockRate * 3  // how long a gust lasts
+	windMinX    = 15             // weakest horizontal push, added to the scroll velocity
+	windMaxX    = 30             // strongest horizontal push
+	windMaxY    = 180            // strongest vertical push, added to gravity
+	MaxLeaves   = 12             // maximum number of leaves at once
+	leafRate    = 15             // how many leaves appear a second, in the wind
+	LeafSize    = TileWidth / 2
+	leafV       = 240 // horizontal velocity of leaves, in the direction of the gust
+)
+
+// A gust of wind slows the gopher down, as a headwind, or speeds it up,
+// as a tailwind, and makes it fall faster or slower.
+type gust struct {
+	x, y  float32    // direction and strength at its peak; x < 0 is a headwind
+	start clock.Time // when the gust arrives
+}
+
+// A Leaf blows across the screen ahead of and during a gust.
+type Leaf struct {
+	X, Y float32 // position, relative to the screen
+	v    float32 // vertical velocity
+	born clock.Time
+}
+
+// nextGust schedules a gust some time after now.
+func (g *Game) nextGust(now clock.Time) gust {
+	x := windMinX + (windMaxX-windMinX)*g.rng.Float32()
+	if g.rng.Intn(2) == 0 {
+		x = -x
+	}
+	y := windMaxY * (g.rng.Float32()*2 - 1)
+	return gust{x, y, now + windMinGap + clock.Time(g.rng.Intn(windMaxGap-windMinGap))}
+}
+
+// windStrength returns how strongly the gust is blowing, from 0 to 1.
+func (g *Game) windStrength() float32 {
+	t := g.LastCalc - g.gust.start
+	if t < 0 || t >= windTime {
+		return 0
+	}
+	return float32(math.Sin(math.Pi * float64(t) / windTime))
+}
+
+// windForce returns the push of the wind on the gopher this frame.
+func (g *Game) windForce() (x, y float32) {
+	if g.Over() || g.Gopher.Grab != grabNone {
+		return 0, 0
+	}
+	s := g.windStrength()
+	return g.gust.x * s, g.gust.y * s
+}
+
+// calcWind schedules gusts and blows the leaves that herald them.
+func (g *Game) calcWind() {
+	if g.Travelled < windStart*TileWidth {
+		g.gust.start = g.LastCalc + windMinGap
+	} else if g.LastCalc >= g.gust.start+windTime {
+		g.gust = g.nextGust(g.LastCalc)
+	}
+
+	// Blow the leaves in the direction of the gust.
+	dir := float32(1)
+	if g.gust.x < 0 {
+		dir = -1
+	}
+	ls := g.Leaves[:0]
+	for _, l := range g.Leaves {
+		l.X += dir * leafV * g.dt()
+		l.Y += (l.v + 30*float32(math.Sin(float64(g.LastCalc-l.born)/8))) * g.dt()
+		if l.X > -LeafSize && l.X < g.width+LeafSize {
+			ls = append(ls, l)
+		}
+	}
+	g.Leaves = ls
+
+	if t := g.gust.start - g.LastCalc; t > windWarning || t <= -windTime {
+		return
+	}
+	if len(g.Leaves) >= MaxLeaves || rand.Float32() >= leafRate*g.dt() {
+		return
+	}
+	x := float32(-LeafSize)
+	if dir < 0 {
+		x = g.width
+	}
+	g.Leaves = append(g.Leaves, Leaf{
+		X:    x,
+		Y:    rand.Float32() * TileHeight * TilesY * 3 / 4,
+		v:    g.gust.y / 3,
+		born: g.LastCalc,
+	})
+}
+
+// WindText returns the sign heralding a gust, which blinks
+// from a second before the gust arrives and stays until it ends.
+func (g *Game) WindText() string {
+	t := g.gust.start - g.LastCalc
+	if g.Choosing() || g.Over() || g.Travelled < windStart*TileWidth || t > windWarning || t <= -windTime {
+		return ""
+	}
+	if t > 0 && t/8%2 == 1 {
+		return ""
+	}
+	if g.gust.x < 0 {
+		return "<< HEADWIND"
+	}
+	return "TAILWIND >>"
+}
@@ -0,0 +1,40 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sim
+
+import "math"
+
+const (
+	WorldW, WorldH = TileWidth * TilesX, TileHeight * TilesY // least of the world shown
+	MaxTilesX      = TilesX * 3                              // most ground tiles shown across
+	WorldTiles     = MaxTilesX + 3                           // most ground tiles kept
+)
+
+// viewTiles returns the number of ground tiles to keep for a world
+// w wide: those shown, and a few more coming up.
+func viewTiles(w float32) int {
+	return int(math.Ceil(float64(w/TileWidth))) + 3
+}
+
+// SetWidth shows a world w wide, extending the ground to fill it.
+func (g *Game) SetWidth(w float32) {
+	g.width = w
+	for g.Tiles < viewTiles(w) {
+		// Carry the ground on as it is.
+		i := g.Tiles
+		g.GroundY[i] = g.GroundY[i-1]
+		g.GroundTex[i] = g.GroundTex[i-1]
+		g.GroundType[i] = tileNormal
+		g.groundSlope[i] = false
+		g.GroundBiome[i] = g.GroundBiome[i-1]
+		g.Scenery[i] = SceneryNone
+		g.Tiles++
+	}
+}
+
+// lastTile returns the index of the last ground tile.
+func (g *Game) lastTile() int {
+	return g.Tiles - 1
+}
@@ -0,0 +1,75 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sim
+
+const (
+	MaxZones    = 2              // maximum number of low gravity zones at once
+	zoneProb    = 40             // 1/probability of a new tile starting a low gravity zone
+	zoneStart   = TilesX * 5     // distance the gopher runs before low gravity zones appear
+	zoneMinLen  = 8              // shortest zone, in tiles
+	zoneMaxLen  = 16             // longest zone, in tiles
+	zoneGravity = 0.6            // gravity in a zone, as a fraction of the usual
+	BalloonSize = TileWidth      // width and height of the balloons marking a zone
+	balloonUp   = 5              // height of the balloons above the ground, in tiles
+	BalloonBob  = TileHeight / 4 // how far the balloons bob up and down
+	BalloonSway = 60             // how long it takes a balloon to bob up and down
+)
+
+// A Zone is a stretch of the world where gravity is weaker,
+// marked by a balloon at each end.
+type Zone struct {
+	X0, X1  float32 // where the zone starts and ends, relative to the ground tiles
+	Y       float32 // y-offset of the balloons
+	gravity float32 // gravity in the zone, as a fraction of the usual
+}
+
+// spawnZone maybe starts a low gravity zone on the newest ground tile.
+func (g *Game) spawnZone() {
+	if g.Travelled < zoneStart*TileWidth || len(g.Zones) >= MaxZones || g.BossActive() || g.rng.Intn(zoneProb) != 0 {
+		return
+	}
+	last := g.lastTile()
+	x := float32(last * TileWidth)
+	if n := len(g.Zones); n > 0 && g.Zones[n-1].X1 > x {
+		// Zones don't overlap.
+		return
+	}
+	n := zoneMinLen + g.rng.Intn(zoneMaxLen-zoneMinLen+1)
+	g.Zones = append(g.Zones, Zone{
+		X0:      x,
+		X1:      x + float32(n*TileWidth),
+		Y:       g.surfaceY(last) - balloonUp*TileHeight,
+		gravity: zoneGravity,
+	})
+}
+
+// shiftZones moves the zones along with the ground tiles.
+func (g *Game) shiftZones() {
+	zs := g.Zones[:0]
+	for _, z := range g.Zones {
+		z.X0 -= TileWidth
+		z.X1 -= TileWidth
+		if z.X1 > 0 {
+			zs = append(zs, z)
+		}
+	}
+	g.Zones = zs
+}
+
+// gravityAt returns the gravity at x, as a fraction of the usual.
+func (g *Game) gravityAt(x float32) float32 {
+	for _, z := range g.Zones {
+		if x >= z.X0 && x < z.X1 {
+			return z.gravity
+		}
+	}
+	return 1
+}
+
+// gopherGravity returns the gravity on the gopher where it is.
+func (g *Game) gopherGravity() float32 {
+	x0, _, x1, _ := g.GopherBounds()
+	return g.physics.Gravity * g.gravityAt((x0+x1)/2)
+}